@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/unsubble/threadinator/internal/executor"
+	"github.com/unsubble/threadinator/internal/history"
 	"github.com/unsubble/threadinator/internal/models"
 	"github.com/unsubble/threadinator/internal/parsers"
 )
@@ -37,24 +43,227 @@ func NewRootCmd(config *models.Config) *cobra.Command {
 			if err := cmd.ParseFlags(args); err != nil {
 				return fmt.Errorf("error parsing flags: %v", err)
 			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if reportPath, _ := cmd.Flags().GetString("rerun-failed"); reportPath != "" {
+				if err := parsers.ParseCommonArgs(config, cmd); err != nil {
+					config.Logger.Errorf("Error: %v", err)
+					os.Exit(1)
+				}
+				report, err := history.ReadRun(reportPath)
+				if err == nil {
+					err = parsers.ApplyVars(config, cmd, report.Vars)
+				}
+				if err != nil {
+					config.Logger.Errorf("Error: %v", err)
+					os.Exit(1)
+				}
+				return executor.RerunFailed(ctx, config, report)
+			}
 			if err := parsers.ParseArgs(config, cmd); err != nil {
 				config.Logger.Errorf("Error: %v", err)
 				os.Exit(1)
 			}
-			return executor.Execute(config)
+			if version, _ := cmd.Flags().GetBool("version"); version || cmd.Flags().Changed("cfg") {
+				return nil
+			}
+			return executor.Execute(ctx, config)
 		},
 		SilenceUsage: true,
 	}
 
 	cmd.Flags().StringP("execute", "e", "", "Semicolon-separated commands to execute")
-	cmd.Flags().IntVarP(&config.ThreadCount, "count", "c", 0, "Number of concurrent threads")
-	cmd.Flags().BoolVarP(&config.UsePipeline, "pipeline", "p", false, "Enable pipeline mode")
-	cmd.Flags().BoolVarP(&config.Verbose, "verbose", "v", false, "Enable verbose output")
-	cmd.Flags().String("log-level", "ERROR", "Set the logging level (INFO, DEBUG, WARN, ERROR)")
-	cmd.Flags().IntP("timeout", "t", config.TimeoutInt, "Timeout duration in seconds")
+	cmd.Flags().StringP("file", "f", "", "Path to a JSON job file")
+	cmd.Flags().String("hosts", "", "Comma-separated inventory groups to run every command on")
+	cmd.Flags().String("inventory", config.Inventory, "Path to a JSON host inventory")
+	cmd.PersistentFlags().IntVarP(&config.ThreadCount, "count", "c", 0, "Number of concurrent threads")
+	cmd.PersistentFlags().BoolVarP(&config.UsePipeline, "pipeline", "p", false, "Enable pipeline mode")
+	cmd.PersistentFlags().BoolVarP(&config.Verbose, "verbose", "v", false, "Enable verbose output")
+	cmd.PersistentFlags().String("log-level", "ERROR", "Set the logging level (INFO, DEBUG, WARN, ERROR)")
+	cmd.PersistentFlags().String("log-format", config.LogFormat, "Set the log output format (text, logfmt, json)")
+	cmd.PersistentFlags().IntP("timeout", "t", config.TimeoutInt, "Timeout duration in seconds")
+	cmd.PersistentFlags().Duration("progress", 0, "Print progress and ETA at this interval (default 5s when given without a value)")
+	cmd.PersistentFlags().Lookup("progress").NoOptDefVal = "5s"
+	cmd.PersistentFlags().Bool("force", false, "Run commands even when their inputs are unchanged")
+	cmd.PersistentFlags().String("report", "", "Write the commands and results of this run to a JSON file")
+	cmd.PersistentFlags().Bool("no-history", false, "Do not record this run in the history database")
+	cmd.PersistentFlags().String("resources", "", "Resource capacity for scheduling (e.g. cpu=8,mem=16G,db=1)")
+	cmd.PersistentFlags().StringArray("var", nil, "Set a variable for ${name} interpolation (NAME=VALUE, repeatable)")
+	addSelectionFlags(cmd)
+	cmd.Flags().String("rerun-failed", "", "Rerun only the failed and timed out commands of a report written with --report")
 	cmd.Flags().String("cfg", "", "Change default settings (must be in JSON syntax)")
 	cmd.Flags().BoolP("version", "V", false, "Show tool version")
 
+	cmd.AddCommand(NewScheduleCmd(config))
+	cmd.AddCommand(NewHistoryCmd(config))
+	cmd.AddCommand(NewResumeCmd(config))
+	cmd.AddCommand(NewWatchCmd(config))
+
+	return cmd
+}
+
+func addSelectionFlags(cmd *cobra.Command) {
+	cmd.Flags().String("only", "", "Comma-separated names of the jobs to run")
+	cmd.Flags().String("tags", "", "Comma-separated tags; run only jobs with at least one of them")
+	cmd.Flags().String("skip", "", "Comma-separated names of jobs not to run")
+	cmd.Flags().Bool("with-deps", false, "Also run the dependencies of the selected jobs")
+	cmd.Flags().Bool("with-dependents", false, "Also run the jobs that depend on the selected jobs")
+}
+
+func NewScheduleCmd(config *models.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run jobs from a job file on their cron schedules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parsers.ParseCommonArgs(config, cmd); err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			jobFilePath, _ := cmd.Flags().GetString("file")
+			jobFile, err := parsers.ParseJobFile(jobFilePath)
+			if err == nil {
+				err = parsers.ApplyVars(config, cmd, jobFile.Vars)
+			}
+			if err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			var store *history.Store
+			if historyPath, _ := cmd.Flags().GetString("history"); historyPath != "" {
+				store = history.NewStore(historyPath)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return executor.Schedule(ctx, config, jobFile, store)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringP("file", "f", "", "Path to a JSON job file")
+	cmd.Flags().String("history", "schedule-history.jsonl", "File to append run history to (empty to disable)")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func NewHistoryCmd(config *models.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history [job...]",
+		Short: "Show durations and outcomes of previous runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parsers.ParseCommonArgs(config, cmd); err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			path := config.HistoryFile
+			if path == "" {
+				path = history.DefaultPath()
+			}
+			records, err := history.NewStore(path).Load()
+			if err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			last, _ := cmd.Flags().GetInt("last")
+			stats := history.Summarize(records, last)
+			if len(args) > 0 {
+				stats = slices.DeleteFunc(stats, func(job *history.JobStats) bool {
+					return !slices.Contains(args, job.Job)
+				})
+			}
+			if len(stats) == 0 {
+				fmt.Printf("No recorded runs in %s\n", path)
+				return nil
+			}
+
+			history.PrintStats(os.Stdout, stats)
+			if len(args) > 0 {
+				for _, job := range stats {
+					history.PrintRuns(os.Stdout, job)
+				}
+			}
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().IntP("last", "n", 20, "Number of most recent runs per job to consider")
+
+	return cmd
+}
+
+func NewResumeCmd(config *models.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume <run-id>",
+		Short: "Rerun the failed, skipped and unstarted commands of a previous run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parsers.ParseCommonArgs(config, cmd); err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			path := config.HistoryFile
+			if path == "" {
+				path = history.DefaultPath()
+			}
+			saved, err := history.NewRunStore(path).Load(args[0])
+			if err == nil {
+				err = parsers.ApplyVars(config, cmd, saved.Vars)
+			}
+			if err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return executor.Resume(ctx, config, saved)
+		},
+		SilenceUsage: true,
+	}
+
+	return cmd
+}
+
+func NewWatchCmd(config *models.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Rerun commands and their dependents whenever their inputs change",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parsers.ParseArgs(config, cmd); err != nil {
+				config.Logger.Errorf("Error: %v", err)
+				os.Exit(1)
+			}
+
+			debounce, _ := cmd.Flags().GetDuration("debounce")
+			poll, _ := cmd.Flags().GetDuration("poll")
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return executor.Watch(ctx, config, debounce, poll)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringP("execute", "e", "", "Semicolon-separated commands to execute")
+	cmd.Flags().StringP("file", "f", "", "Path to a JSON job file")
+	addSelectionFlags(cmd)
+	cmd.Flags().Duration("debounce", 300*time.Millisecond, "Wait this long after the last change before rerunning")
+	cmd.Flags().Duration("poll", 0, "Poll inputs at this interval instead of using file notifications")
+
 	return cmd
 }
 
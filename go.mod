@@ -5,8 +5,11 @@ go 1.23.0
 toolchain go1.24.1
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/crypto v0.36.0
 )
 
 require (
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type Entry struct {
+	Hash    string            `json:"hash"`
+	Updated time.Time         `json:"updated"`
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+type Store struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]*Entry
+	changed map[string]*Entry
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]*Store)
+)
+
+func DefaultPath() string {
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.TempDir()
+		}
+		cacheHome = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(cacheHome, "threadinator", "inputs.json")
+}
+
+// Open returns the store for path, shared by every run in the process, with
+// the entries other processes have saved since it was last read.
+func Open(path string) (*Store, error) {
+	key := path
+	if abs, err := filepath.Abs(path); err == nil {
+		key = abs
+	}
+
+	storesMu.Lock()
+	store, has := stores[key]
+	if !has {
+		store = &Store{path: path, changed: make(map[string]*Entry)}
+		stores[key] = store
+	}
+	storesMu.Unlock()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// load rereads the file and keeps the entries updated since the last save on
+// top of it.
+func (s *Store) load() error {
+	entries := make(map[string]*Entry)
+	data, err := os.ReadFile(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return models.NewFileOpenError(s.path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return models.NewCacheError(s.path, err)
+		}
+	}
+
+	maps.Copy(entries, s.changed)
+	s.entries = entries
+	return nil
+}
+
+func (s *Store) Lookup(key string) *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries[key]
+}
+
+func (s *Store) Update(key string, entry *Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry
+	s.changed[key] = entry
+}
+
+// Save merges the entries updated in this process into the file, so runs
+// saving the same cache do not drop each other's entries.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.changed) == 0 {
+		return nil
+	}
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return models.NewCacheError(s.path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return models.NewCacheError(s.path, err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return models.NewCacheError(s.path, err)
+	}
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(temp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), s.path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return models.NewCacheError(s.path, err)
+	}
+	clear(s.changed)
+	return nil
+}
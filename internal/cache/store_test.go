@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestStoresMergeOnSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.json")
+	first, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Open(filepath.Join(filepath.Dir(path), ".", "inputs.json"))
+	if err != nil || second != first {
+		t.Fatalf("Open() = %p, %v; want the shared store %p", second, err, first)
+	}
+
+	var wg sync.WaitGroup
+	for run := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store, err := Open(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			store.Update(fmt.Sprintf("run %d", run), &Entry{Hash: "local"})
+			if err := store.Save(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Another process saves an entry of its own in the meantime.
+	entries := readEntries(t, path)
+	entries["other process"] = &Entry{Hash: "other"}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first.Update("run 0", &Entry{Hash: "updated"})
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries = readEntries(t, path)
+	if len(entries) != 9 || entries["other process"] == nil || entries["run 0"].Hash != "updated" {
+		t.Fatalf("saved entries %v, want the 8 runs and the other process", slices.Sorted(maps.Keys(entries)))
+	}
+	if first.Lookup("other process") == nil {
+		t.Error("the store did not pick up the other process's entry")
+	}
+}
+
+func readEntries(t *testing.T, path string) map[string]*Entry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make(map[string]*Entry)
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
@@ -0,0 +1,102 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type Executor interface {
+	Start(ctx context.Context) error
+	Wait() error
+	Stdin(reader io.Reader)
+	Stdout() io.Reader
+	Stderr() io.Reader
+	Signal(sig os.Signal) error
+}
+
+func newExecutor(w *Worker, command *models.Command, inputFiles []string) (Executor, error) {
+	env, err := resolveEnvironment(w.config, command)
+	if err != nil {
+		return nil, err
+	}
+	env.inputs = inputFiles
+
+	switch backend := command.BackendName(); backend {
+	case models.BackendLocal:
+		return newLocalExecutor(command, env.local(), env.dir, env.inputs), nil
+	case models.BackendSSH:
+		if command.Host == nil {
+			return nil, models.NewBackendError(backend, "no host configured")
+		}
+		if len(env.inputs) > 0 {
+			return nil, models.NewBackendError(backend, "stdin=files is not supported on remote hosts")
+		}
+		if usesOutputFile(command) {
+			return nil, models.NewBackendError(backend, "only the stdout output is supported on remote hosts")
+		}
+		return newSSHExecutor(w.config, command, env, w.logger()), nil
+	case models.BackendContainer:
+		if command.Container == nil {
+			return nil, models.NewBackendError(backend, "no image configured")
+		}
+		return newContainerExecutor(w.config, command, env, w.logger())
+	case models.BackendFake:
+		return newFakeExecutor(command), nil
+	default:
+		return nil, models.NewBackendError(backend, "unknown backend")
+	}
+}
+
+func feedStdin(writer io.WriteCloser, reader io.Reader) {
+	io.Copy(writer, reader)
+	writer.Close()
+}
+
+type processStreams struct {
+	stdin        io.Reader
+	stdout       *io.PipeReader
+	stdoutWriter *io.PipeWriter
+	stderr       *io.PipeReader
+	stderrWriter *io.PipeWriter
+	done         chan struct{}
+	err          error
+}
+
+func newProcessStreams() processStreams {
+	stdout, stdoutWriter := io.Pipe()
+	stderr, stderrWriter := io.Pipe()
+	return processStreams{
+		stdout:       stdout,
+		stdoutWriter: stdoutWriter,
+		stderr:       stderr,
+		stderrWriter: stderrWriter,
+		done:         make(chan struct{}),
+	}
+}
+
+func (s *processStreams) Stdin(reader io.Reader) {
+	s.stdin = reader
+}
+
+func (s *processStreams) Stdout() io.Reader {
+	return s.stdout
+}
+
+func (s *processStreams) Stderr() io.Reader {
+	return s.stderr
+}
+
+func (s *processStreams) Wait() error {
+	<-s.done
+	return s.err
+}
+
+func (s *processStreams) finish(err error) {
+	s.err = err
+	s.stdoutWriter.Close()
+	s.stderrWriter.Close()
+	close(s.done)
+}
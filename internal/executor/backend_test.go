@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestRemoteCommandsRejectOutputFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []string
+		stdin   string
+	}{
+		{name: "named output", outputs: []string{"stdout", "version"}},
+		{name: "stdin files", stdin: models.StdinFiles},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			remote := fakeCommand("remote", "echo remote", 0)
+			remote.Backend = models.BackendSSH
+			remote.Host = &models.Host{User: "nobody", Address: "127.0.0.1:1"}
+			remote.Outputs = test.outputs
+			remote.Stdin = test.stdin
+			config := newTestConfig(fakeCommand("parent", "echo parent"), remote)
+			config.UsePipeline = true
+
+			errs := runScheduler(t, config, nil, nil)
+			var backendErr *models.BackendError
+			if len(errs) != 1 || !errors.As(errs[0], &backendErr) {
+				t.Fatalf("got errors %v, want one BackendError", errs)
+			}
+		})
+	}
+}
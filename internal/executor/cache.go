@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/cache"
+	"github.com/unsubble/threadinator/internal/models"
+	"github.com/unsubble/threadinator/internal/parsers"
+)
+
+func openCache(config *models.Config) *cache.Store {
+	if !slices.ContainsFunc(config.Commands, func(command *models.Command) bool { return len(command.Inputs) > 0 }) {
+		return nil
+	}
+
+	store, err := cache.Open(cache.DefaultPath())
+	if err != nil {
+		config.Logger.WithError(err).Warn("Failed to open input cache")
+		return nil
+	}
+	return store
+}
+
+func saveCache(config *models.Config, store *cache.Store) {
+	if store == nil {
+		return
+	}
+	if err := store.Save(); err != nil {
+		config.Logger.WithError(err).Warn("Failed to save input cache")
+	}
+}
+
+func (w *Worker) checkInputs() (string, error) {
+	if w.run.cache == nil || len(w.command.Inputs) == 0 {
+		return "", nil
+	}
+
+	command, err := parsers.InterpolateCommand(w.command, w.variables())
+	if err != nil {
+		return "", err
+	}
+
+	dir := commandDir(w.config, command)
+	hash, err := inputHash(command, dir, w.parentHashes())
+	if err != nil {
+		return "", err
+	}
+
+	result := w.config.Results[w.index]
+	result.Hash = hash
+	if w.config.Force || w.run.streams[w.index] != nil {
+		return hash, nil
+	}
+
+	entry := w.run.cache.Lookup(cacheKey(w.command))
+	if entry == nil || entry.Hash != hash {
+		return hash, nil
+	}
+
+	outputs, err := expandGlobs(dir, command.OutputFiles)
+	if err != nil || len(command.OutputFiles) > 0 && len(outputs) == 0 {
+		return hash, nil
+	}
+
+	result.Outputs = entry.Outputs
+	result.Cached = true
+	return hash, models.NewSkipError("inputs unchanged since the last successful run")
+}
+
+func (w *Worker) storeInputs(hash string) {
+	if hash == "" {
+		return
+	}
+	w.run.cache.Update(cacheKey(w.command), &cache.Entry{
+		Hash:    hash,
+		Updated: time.Now(),
+		Outputs: w.config.Results[w.index].Outputs,
+	})
+}
+
+func (w *Worker) parentHashes() []string {
+	var hashes []string
+	for _, dependency := range w.command.Dependencies {
+		parent := w.config.Results[dependency]
+		switch {
+		case parent.Hash != "":
+			hashes = append(hashes, parent.Hash)
+		case !parent.Finished() || parent.Status != models.RunStatusSkipped:
+			// A parent without inputs, or one still streaming to this command,
+			// changes whatever it produces each time it runs.
+			hashes = append(hashes, "ran "+parent.Start.Format(time.RFC3339Nano))
+		}
+	}
+	return hashes
+}
+
+// cacheKey tells apart the copies of a command made for each host and repeat.
+func cacheKey(command *models.Command) string {
+	key := command.Key()
+	if command.Host != nil {
+		key += " @" + command.Host.String()
+	}
+	if command.Repeat > 0 {
+		key += fmt.Sprintf(" #%d", command.Repeat)
+	}
+	return key
+}
+
+func commandDir(config *models.Config, command *models.Command) string {
+	if command.Cwd != "" {
+		return command.Cwd
+	}
+	return config.Cwd
+}
+
+func inputHash(command *models.Command, dir string, parents []string) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "command %q %q\n", command.Command, command.Args)
+	fmt.Fprintf(hash, "backend %s cwd %q\n", command.BackendName(), dir)
+	if command.Host != nil {
+		fmt.Fprintf(hash, "host %s\n", command.Host)
+	}
+	if command.Container != nil {
+		fmt.Fprintf(hash, "image %s\n", command.Container.Image)
+	}
+	for _, name := range slices.Sorted(maps.Keys(command.Env)) {
+		fmt.Fprintf(hash, "env %s=%q\n", name, command.Env[name])
+	}
+	for _, pattern := range command.OutputFiles {
+		fmt.Fprintf(hash, "output %q\n", pattern)
+	}
+	for _, parent := range parents {
+		fmt.Fprintf(hash, "parent %s\n", parent)
+	}
+
+	files, err := expandGlobs(dir, command.Inputs)
+	if err != nil {
+		return "", err
+	}
+	for _, path := range files {
+		sum, err := fileHash(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "file %q %s\n", path, sum)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func fileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", models.NewFileOpenError(path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", models.NewFileOpenError(path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,142 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestCacheSkipsUnchangedCommands(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands func(dir string) []*models.Command
+		pipeline bool
+		statuses []string
+		stdout   string
+	}{
+		{
+			name: "unchanged inputs",
+			commands: func(dir string) []*models.Command {
+				return []*models.Command{withInputs(fakeCommand("build", "echo build"), dir)}
+			},
+			statuses: []string{models.RunStatusSkipped},
+		},
+		{
+			name: "copies per host and repeat keep their own entries",
+			commands: func(dir string) []*models.Command {
+				var commands []*models.Command
+				for _, address := range []string{"web1:22", "web2:22"} {
+					for repeat := range 2 {
+						command := withInputs(fakeCommand("build", "echo build"), dir)
+						command.Host = &models.Host{User: "deploy", Address: address}
+						command.Repeat = repeat
+						commands = append(commands, command)
+					}
+				}
+				return commands
+			},
+			statuses: []string{models.RunStatusSkipped, models.RunStatusSkipped, models.RunStatusSkipped, models.RunStatusSkipped},
+		},
+		{
+			name: "success() holds after a cache hit",
+			commands: func(dir string) []*models.Command {
+				deploy := fakeCommand("deploy", "echo deploy", 0)
+				deploy.If = "success()"
+				return []*models.Command{withInputs(fakeCommand("build", "echo build"), dir), deploy}
+			},
+			statuses: []string{models.RunStatusSkipped, models.RunStatusSuccess},
+		},
+		{
+			name: "parent without inputs ran again",
+			commands: func(dir string) []*models.Command {
+				return []*models.Command{fakeCommand("prepare", "echo prepare"), withInputs(fakeCommand("build", "echo build", 0), dir)}
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSuccess},
+		},
+		{
+			name: "pipeline producer keeps feeding its consumer",
+			commands: func(dir string) []*models.Command {
+				consumer := fakeCommand("consumer", "cat", 0)
+				consumer.Outputs = []string{"stdout"}
+				return []*models.Command{withInputs(fakeCommand("build", "echo build"), dir), consumer}
+			},
+			pipeline: true,
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSuccess},
+			stdout:   "echo build",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("XDG_CACHE_HOME", t.TempDir())
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("one"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			var config *models.Config
+			for run := 0; run < 2; run++ {
+				config = newTestConfig(test.commands(dir)...)
+				config.UsePipeline = test.pipeline
+				if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+					t.Fatalf("run %d failed: %v", run, errs)
+				}
+			}
+
+			if got := statuses(config); strings.Join(got, ",") != strings.Join(test.statuses, ",") {
+				t.Errorf("second run statuses = %v, want %v", got, test.statuses)
+			}
+			if last := config.Results[len(config.Results)-1]; !strings.Contains(last.Outputs["stdout"], test.stdout) {
+				t.Errorf("consumer stdout = %q, want %q", last.Outputs["stdout"], test.stdout)
+			}
+		})
+	}
+}
+
+func withInputs(command *models.Command, dir string) *models.Command {
+	command.Inputs = []string{"*.txt"}
+	command.Cwd = dir
+	return command
+}
+
+func TestSkipsAreLogged(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("one"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var output bytes.Buffer
+	for run := 0; run < 2; run++ {
+		notify := fakeCommand("notify", "echo failed", 0)
+		notify.If = "failure()"
+		config := newTestConfig(withInputs(fakeCommand("build", "echo build"), dir), notify)
+		output.Reset()
+		config.Logger.SetOutput(&output)
+		config.Logger.SetFormatter(&logrus.JSONFormatter{})
+		config.Logger.SetLevel(logrus.ErrorLevel)
+		if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+			t.Fatalf("run %d failed: %v", run, errs)
+		}
+	}
+
+	reasons := make(map[float64]string)
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		if entry["msg"] == "Command skipped" {
+			reasons[entry["index"].(float64)] = entry["reason"].(string)
+		}
+	}
+	if !strings.Contains(reasons[0], "inputs unchanged") || !strings.Contains(reasons[1], "not met") {
+		t.Fatalf("skip reasons = %v, want the cache hit and the condition", reasons)
+	}
+}
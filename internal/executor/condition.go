@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+	"github.com/unsubble/threadinator/internal/parsers"
+)
+
+func evaluateCondition(config *models.Config, index int) error {
+	command := config.Commands[index]
+	if command.If == "" {
+		return nil
+	}
+
+	vars := outputVariables(config.Results)
+	maps.Copy(vars, config.Vars)
+	maps.Copy(vars, command.Vars)
+	vars["index"] = strconv.Itoa(index)
+	vars["repeat"] = strconv.Itoa(command.Repeat)
+	vars["run_id"] = config.RunID
+
+	context := &parsers.ConditionContext{
+		Vars: vars,
+		Env:  command.Env,
+		Dir:  commandDir(config, command),
+	}
+	for _, dependency := range command.Dependencies {
+		parent := config.Results[dependency]
+		status := parent.Status
+		if parent.Cached {
+			// A cache hit stands for the last successful run of the parent.
+			status = models.RunStatusSuccess
+		}
+		context.Parents = append(context.Parents, status)
+	}
+
+	met, err := parsers.EvaluateCondition(command.If, context)
+	if err != nil {
+		return err
+	}
+	if !met {
+		return models.NewSkipError(fmt.Sprintf("condition %q not met", command.If))
+	}
+	return nil
+}
+
+func (s *scheduler) skipCommand(index int, reason error) {
+	command := s.config.Commands[index]
+	result := s.config.Results[index]
+	result.Start = time.Now()
+	s.run.streams.finish(command, index)
+	result.Finish(reason)
+
+	if result.Status == models.RunStatusSkipped {
+		s.run.logSkip(s.config.Logger.WithFields(logrus.Fields{"index": index, "command": command.Command}), reason)
+	} else {
+		s.errorChan <- reason
+	}
+	s.run.events <- commandEvent{kind: commandFinished, index: index}
+}
+
+// logSkip reports why a command was skipped. Like progress it is shown whatever
+// the log level, with the fields of the given entry.
+func (r *runState) logSkip(entry *logrus.Entry, reason error) {
+	r.status.WithFields(entry.Data).WithField("reason", reason.Error()).Info("Command skipped")
+}
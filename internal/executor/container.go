@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const defaultContainerRuntime = "docker"
+
+type containerExecutor struct {
+	*localExecutor
+	runtime string
+	name    string
+	logger  *logrus.Entry
+}
+
+func newContainerExecutor(config *models.Config, command *models.Command, env *environment, logger *logrus.Entry) (*containerExecutor, error) {
+	runtime := command.Container.Runtime
+	if runtime == "" {
+		runtime = config.Runtime
+	}
+	if runtime == "" {
+		runtime = defaultContainerRuntime
+	}
+
+	workDir, err := filepath.Abs(env.dir)
+	if err != nil {
+		return nil, models.NewBackendError(models.BackendContainer, err.Error())
+	}
+
+	name := fmt.Sprintf("threadinator-%d-%d", os.Getpid(), time.Now().UnixNano())
+	args := containerRunArgs(command, env, name, workDir)
+	logger.WithFields(logrus.Fields{"runtime": runtime, "container": name}).Debugf("Running container: %v", args)
+
+	return &containerExecutor{
+		localExecutor: newLocalExecutor(&models.Command{Command: runtime, Args: args}, os.Environ(), "", nil),
+		runtime:       runtime,
+		name:          name,
+		logger:        logger,
+	}, nil
+}
+
+func containerRunArgs(command *models.Command, env *environment, name, workDir string) []string {
+	container := command.Container
+	args := []string{
+		"run", "--rm", "-i",
+		"--name", name,
+		"-v", workDir + ":" + workDir,
+		"-w", workDir,
+	}
+
+	if outputFile := env.set[outputFileEnv]; outputFile != "" {
+		args = append(args, "-v", outputFile+":"+outputFile)
+	}
+	for _, inputFile := range env.inputs {
+		args = append(args, "-v", inputFile+":"+inputFile+":ro")
+	}
+	for _, name := range env.names() {
+		args = append(args, "-e", name+"="+env.set[name])
+	}
+	if container.CPUs != "" {
+		args = append(args, "--cpus", container.CPUs)
+	}
+	if container.Memory != "" {
+		args = append(args, "--memory", container.Memory)
+	}
+
+	args = append(args, container.Image, command.Command)
+	return append(args, command.Args...)
+}
+
+func (e *containerExecutor) Signal(sig os.Signal) error {
+	var args []string
+	if sig == os.Kill {
+		args = []string{"stop", "--time", "0", e.name}
+	} else if s, ok := sig.(syscall.Signal); ok {
+		args = []string{"kill", "--signal", fmt.Sprintf("%d", int(s)), e.name}
+	} else {
+		return e.localExecutor.Signal(sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if output, err := exec.CommandContext(ctx, e.runtime, args...).CombinedOutput(); err != nil {
+		e.logger.WithError(err).WithField("container", e.name).Warnf("Failed to signal container: %s", output)
+	}
+
+	if sig == os.Kill {
+		return e.localExecutor.Signal(sig)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+// fakeDocker puts a docker script on PATH that logs its arguments, one
+// invocation per line, and runs the container command when it is sleep.
+func fakeDocker(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	log := filepath.Join(dir, "docker.log")
+	script := `#!/bin/sh
+echo "$@" >> "$DOCKER_LOG"
+if [ "$1" = run ]; then
+	for last; do :; done
+	case "$*" in
+	*" sleep "*) exec sleep "$last" ;;
+	esac
+	echo ran
+fi
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DOCKER_LOG", log)
+	return log
+}
+
+func dockerCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func containerCommand(line, dir string) *models.Command {
+	command := fakeCommand("build", line)
+	command.Backend = models.BackendContainer
+	command.Cwd = dir
+	command.Container = &models.Container{Image: "alpine:3", CPUs: "1.5", Memory: "256m"}
+	return command
+}
+
+func TestContainerRunArgs(t *testing.T) {
+	log := fakeDocker(t)
+	dir := t.TempDir()
+	command := containerCommand("make all", dir)
+	command.Env = map[string]string{"MODE": "release"}
+	command.Outputs = []string{"stdout", "version"}
+	config := newTestConfig(command)
+
+	if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+		t.Fatalf("container command failed: %v", errs)
+	}
+	if got := config.Results[0].Outputs["stdout"]; got != "ran" {
+		t.Errorf("stdout = %q, want the container's output", got)
+	}
+
+	calls := dockerCalls(t, log)
+	if len(calls) != 1 {
+		t.Fatalf("docker calls = %q, want one run", calls)
+	}
+	run := calls[0]
+	for _, want := range []string{
+		"run --rm -i --name threadinator-",
+		" -v " + dir + ":" + dir + " -w " + dir + " ",
+		" -e MODE=release ",
+		" -e THREADINATOR_OUTPUT=",
+		" --cpus 1.5 --memory 256m alpine:3 make all",
+	} {
+		if !strings.Contains(run, want) {
+			t.Errorf("docker %s\nis missing %q", run, want)
+		}
+	}
+
+	outputFile := run[strings.Index(run, "THREADINATOR_OUTPUT=")+len("THREADINATOR_OUTPUT="):]
+	outputFile = outputFile[:strings.IndexByte(outputFile, ' ')]
+	if !strings.Contains(run, " -v "+outputFile+":"+outputFile+" ") {
+		t.Errorf("docker %s\ndoes not mount the output file %s", run, outputFile)
+	}
+}
+
+func TestContainerTimeoutStopsContainer(t *testing.T) {
+	log := fakeDocker(t)
+	config := newTestConfig(containerCommand("sleep 10", t.TempDir()))
+	config.Timeout = 200 * time.Millisecond
+
+	started := time.Now()
+	errs := runScheduler(t, config, nil, nil)
+	if len(errs) != 1 || config.Results[0].Status != models.RunStatusTimeout {
+		t.Fatalf("got %v with status %s, want a timeout", errs, config.Results[0].Status)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("timed out container was not stopped promptly: %v", elapsed)
+	}
+
+	calls := dockerCalls(t, log)
+	name := strings.Fields(calls[0])[4]
+	if want := "stop --time 0 " + name; calls[len(calls)-1] != want {
+		t.Errorf("docker calls = %q, want the last to be %q", calls, want)
+	}
+}
@@ -0,0 +1,206 @@
+package executor
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/history"
+	"github.com/unsubble/threadinator/internal/models"
+	"github.com/unsubble/threadinator/internal/parsers"
+)
+
+type scheduledUnit struct {
+	name         string
+	schedule     cron.Schedule
+	allowOverlap bool
+	commands     []*models.Command
+	running      atomic.Int32
+}
+
+func Schedule(ctx context.Context, config *models.Config, jobFile *models.JobFile, store *history.Store) error {
+	units, err := buildScheduledUnits(jobFile)
+	if err != nil {
+		config.Logger.Errorf("Schedule resolution failed: %v", err)
+		return err
+	}
+
+	if len(units) == 0 {
+		config.Logger.Warn("No scheduled jobs or groups found")
+		return nil
+	}
+
+	limit := config.ThreadCount
+	if limit <= 0 {
+		limit = runtime.NumCPU()
+	}
+	slots := make(chan struct{}, limit)
+
+	config.Logger.WithFields(logrus.Fields{"units": len(units), "threads": limit}).Info("Starting scheduler")
+	var wg sync.WaitGroup
+	for _, unit := range units {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runSchedule(ctx, config, unit, store, slots)
+		}()
+	}
+	wg.Wait()
+
+	config.Logger.Info("Scheduler stopped")
+	return nil
+}
+
+func buildScheduledUnits(jobFile *models.JobFile) ([]*scheduledUnit, error) {
+	jobs := make(map[string]*models.Job)
+	for _, job := range jobFile.Jobs {
+		jobs[job.Name] = job
+	}
+
+	var units []*scheduledUnit
+	addUnit := func(name, expression string, allowOverlap bool, members []string) error {
+		schedule, err := parsers.ParseSchedule(expression)
+		if err != nil {
+			return err
+		}
+		commands, err := parsers.BuildCommands(withDependencies(jobFile.Jobs, jobs, members), 0)
+		if err != nil {
+			return err
+		}
+		units = append(units, &scheduledUnit{
+			name:         name,
+			schedule:     schedule,
+			allowOverlap: allowOverlap,
+			commands:     commands,
+		})
+		return nil
+	}
+
+	for _, job := range jobFile.Jobs {
+		if job.Schedule == "" {
+			continue
+		}
+		if err := addUnit(job.Name, job.Schedule, job.AllowOverlap, []string{job.Name}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, group := range jobFile.Groups {
+		if err := addUnit(group.Name, group.Schedule, group.AllowOverlap, group.Jobs); err != nil {
+			return nil, err
+		}
+	}
+
+	return units, nil
+}
+
+func withDependencies(ordered []*models.Job, jobs map[string]*models.Job, members []string) []*models.Job {
+	selected := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		name, _, _ = strings.Cut(name, "[")
+		if selected[name] {
+			return
+		}
+		selected[name] = true
+		if job, has := jobs[name]; has {
+			for _, dependency := range job.DependsOn {
+				visit(dependency)
+			}
+		}
+	}
+	for _, name := range members {
+		visit(name)
+	}
+
+	var result []*models.Job
+	for _, job := range ordered {
+		if selected[job.Name] {
+			result = append(result, job)
+		}
+	}
+	return result
+}
+
+func runSchedule(ctx context.Context, config *models.Config, unit *scheduledUnit, store *history.Store, slots chan struct{}) {
+	var runs sync.WaitGroup
+	defer runs.Wait()
+
+	logger := config.Logger.WithField("job", unit.name)
+	for {
+		next := unit.schedule.Next(time.Now())
+		logger.WithField("next", next).Debug("Waiting for next scheduled run")
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !unit.allowOverlap && unit.running.Load() > 0 {
+			logger.Warn("Previous run still in progress, skipping")
+			recordRun(config, store, &models.RunRecord{
+				Job:    unit.name,
+				Start:  time.Now(),
+				End:    time.Now(),
+				Status: models.RunStatusSkipped,
+				Error:  "previous run still in progress",
+			})
+			continue
+		}
+
+		unit.running.Add(1)
+		runs.Add(1)
+		go func() {
+			defer runs.Done()
+			defer unit.running.Add(-1)
+			executeUnit(ctx, config, unit, store, slots)
+		}()
+	}
+}
+
+func executeUnit(ctx context.Context, config *models.Config, unit *scheduledUnit, store *history.Store, slots chan struct{}) {
+	runConfig := *config
+	runConfig.Commands = unit.commands
+	if runConfig.ThreadCount <= 0 {
+		runConfig.ThreadCount = len(unit.commands)
+	}
+
+	config.Logger.WithField("job", unit.name).Info("Starting scheduled run")
+	record := &models.RunRecord{
+		Job:    unit.name,
+		Start:  time.Now(),
+		Status: models.RunStatusSuccess,
+	}
+
+	if err := executeUntil(ctx, &runConfig, nil, slots); err != nil {
+		record.Status = models.RunStatusFailed
+		record.Error = err.Error()
+	}
+
+	record.End = time.Now()
+	record.Duration = record.End.Sub(record.Start)
+	recordRun(config, store, record)
+}
+
+func recordRun(config *models.Config, store *history.Store, record *models.RunRecord) {
+	config.Logger.WithFields(logrus.Fields{
+		"job":      record.Job,
+		"status":   record.Status,
+		"duration": record.Duration,
+	}).Info("Scheduled run finished")
+
+	if store == nil {
+		return
+	}
+	if err := store.Append(record); err != nil {
+		config.Logger.Errorf("Failed to record run history: %v", err)
+	}
+}
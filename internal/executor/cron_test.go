@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestScheduledRunsShareSlots(t *testing.T) {
+	config := newTestConfig()
+	slots := make(chan struct{}, 1)
+	units := []*scheduledUnit{
+		{name: "first", commands: []*models.Command{fakeCommand("first", "sleep 100ms")}},
+		{name: "second", commands: []*models.Command{fakeCommand("second", "sleep 100ms")}},
+	}
+
+	started := time.Now()
+	var runs sync.WaitGroup
+	for _, unit := range units {
+		runs.Add(1)
+		go func() {
+			defer runs.Done()
+			executeUnit(context.Background(), config, unit, nil, slots)
+		}()
+	}
+	runs.Wait()
+
+	if elapsed := time.Since(started); elapsed < 200*time.Millisecond {
+		t.Errorf("two runs sharing one slot finished in %v, want them to take turns", elapsed)
+	}
+}
@@ -1,25 +1,36 @@
 package executor
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
 	"github.com/unsubble/threadinator/internal/models"
 )
 
 func resolveExecutionOrder(config *models.Config) ([]int, error) {
 	config.Logger.Debug("Resolving execution order based on dependencies.")
+	if len(config.Only) > 0 || len(config.Tags) > 0 || len(config.Skip) > 0 {
+		commands, err := selectCommands(config)
+		if err != nil {
+			return nil, err
+		}
+		config.Commands = commands
+	}
+
 	graph := make(map[int][]int)
 	inDegree := make(map[int]int)
 
 	commands := config.Commands
 
 	for i, cmd := range commands {
-		if cmd.Dependency != nil {
-			depIdx := *cmd.Dependency
+		for _, depIdx := range cmd.Dependencies {
 			if depIdx < 0 || depIdx >= len(commands) {
 				return nil, models.NewDependencyError(depIdx, i)
 			}
 			graph[depIdx] = append(graph[depIdx], i)
 			inDegree[i]++
-			config.Logger.Debugf("Command %d depends on command %d", i, depIdx)
+			config.Logger.WithFields(logrus.Fields{"index": i, "dependency": depIdx}).Debug("Command dependency registered")
 		}
 	}
 
@@ -56,3 +67,113 @@ func topologicalSort(graph map[int][]int, inDegree map[int]int, totalCommands in
 
 	return order, nil
 }
+
+func selectCommands(config *models.Config) ([]*models.Command, error) {
+	commands := config.Commands
+	dependents, err := dependentsOf(commands)
+	if err != nil {
+		return nil, err
+	}
+
+	skipped := make([]bool, len(commands))
+	chosen := make([]bool, len(commands))
+	for i, cmd := range commands {
+		skipped[i] = matchesJob(cmd, config.Skip)
+		chosen[i] = !skipped[i] &&
+			(len(config.Only) == 0 || matchesJob(cmd, config.Only)) &&
+			(len(config.Tags) == 0 || slices.ContainsFunc(cmd.Tags, func(tag string) bool {
+				return slices.Contains(config.Tags, tag)
+			}))
+	}
+
+	selected := slices.Clone(chosen)
+	if config.WithDeps {
+		closeSelection(chosen, selected, skipped, func(i int) []int { return commands[i].Dependencies })
+	}
+	if config.WithDependents {
+		closeSelection(chosen, selected, skipped, func(i int) []int { return dependents[i] })
+	}
+
+	filtered := subsetCommands(commands, selected, func(cmd, dependency *models.Command) {
+		config.Logger.WithFields(logrus.Fields{
+			"command":    cmd.Key(),
+			"dependency": dependency.Key(),
+		}).Warn("Dependency is not selected, running without it")
+	})
+	if len(filtered) == 0 {
+		return nil, models.NewEmptySelectionError()
+	}
+
+	config.Logger.WithFields(logrus.Fields{"selected": len(filtered), "total": len(commands)}).Info("Selected commands")
+	return filtered, nil
+}
+
+func dependentsOf(commands []*models.Command) ([][]int, error) {
+	dependents := make([][]int, len(commands))
+	for i, cmd := range commands {
+		for _, depIdx := range cmd.Dependencies {
+			if depIdx < 0 || depIdx >= len(commands) {
+				return nil, models.NewDependencyError(depIdx, i)
+			}
+			dependents[depIdx] = append(dependents[depIdx], i)
+		}
+	}
+	return dependents, nil
+}
+
+func subsetCommands(commands []*models.Command, selected []bool, cut func(cmd, dependency *models.Command)) []*models.Command {
+	positions := make(map[int]int)
+	var filtered []*models.Command
+	for i, cmd := range commands {
+		if selected[i] {
+			positions[i] = len(filtered)
+			clone := *cmd
+			filtered = append(filtered, &clone)
+		}
+	}
+
+	for _, cmd := range filtered {
+		var dependencies []int
+		for _, depIdx := range cmd.Dependencies {
+			if position, has := positions[depIdx]; has {
+				dependencies = append(dependencies, position)
+			} else if cut != nil {
+				cut(cmd, commands[depIdx])
+			}
+		}
+		cmd.Dependencies = dependencies
+	}
+	return filtered
+}
+
+func closeSelection(chosen, selected, skipped []bool, neighbors func(int) []int) {
+	var queue []int
+	for i := range chosen {
+		if chosen[i] {
+			queue = append(queue, i)
+		}
+	}
+
+	visited := slices.Clone(chosen)
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range neighbors(curr) {
+			if visited[neighbor] || skipped[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+			selected[neighbor] = true
+			queue = append(queue, neighbor)
+		}
+	}
+}
+
+func matchesJob(cmd *models.Command, names []string) bool {
+	for _, name := range names {
+		if cmd.Key() == name || strings.HasPrefix(cmd.Name, name+"[") {
+			return true
+		}
+	}
+	return false
+}
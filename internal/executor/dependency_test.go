@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type warningRecorder struct {
+	warnings []string
+}
+
+func (r *warningRecorder) Levels() []logrus.Level {
+	return []logrus.Level{logrus.WarnLevel}
+}
+
+func (r *warningRecorder) Fire(entry *logrus.Entry) error {
+	r.warnings = append(r.warnings, fmt.Sprintf("%s -> %s", entry.Data["command"], entry.Data["dependency"]))
+	return nil
+}
+
+func selectionGraph() []*models.Command {
+	lint := fakeCommand("lint", "make lint")
+	lint.Tags = []string{"fast"}
+	fast := fakeCommand("test[go=1.23]", "make test", 1)
+	fast.Tags = []string{"fast"}
+	return []*models.Command{
+		lint,
+		fakeCommand("build", "make build"),
+		fast,
+		fakeCommand("test[go=1.22]", "make test", 1),
+		fakeCommand("deploy", "make deploy", 2, 3),
+	}
+}
+
+func TestSelectCommands(t *testing.T) {
+	tests := []struct {
+		name       string
+		only       []string
+		tags       []string
+		skip       []string
+		deps       bool
+		dependents bool
+		want       []string
+		warnings   []string
+		empty      bool
+	}{
+		{
+			name:     "only a matrix job",
+			only:     []string{"test"},
+			want:     []string{"test[go=1.23]", "test[go=1.22]"},
+			warnings: []string{"test[go=1.23] -> build", "test[go=1.22] -> build"},
+		},
+		{
+			name: "only one expansion with deps",
+			only: []string{"test[go=1.22]"},
+			deps: true,
+			want: []string{"build", "test[go=1.22] <- 0"},
+		},
+		{
+			name:       "with dependents",
+			only:       []string{"build"},
+			dependents: true,
+			want:       []string{"build", "test[go=1.23] <- 0", "test[go=1.22] <- 0", "deploy <- 1,2"},
+		},
+		{
+			name:     "tags",
+			tags:     []string{"fast", "slow"},
+			want:     []string{"lint", "test[go=1.23]"},
+			warnings: []string{"test[go=1.23] -> build"},
+		},
+		{
+			name: "skip alone",
+			skip: []string{"lint"},
+			want: []string{"build", "test[go=1.23] <- 0", "test[go=1.22] <- 0", "deploy <- 1,2"},
+		},
+		{
+			name:     "deps never add skipped jobs",
+			only:     []string{"deploy"},
+			skip:     []string{"build", "test[go=1.22]"},
+			deps:     true,
+			want:     []string{"test[go=1.23]", "deploy <- 0"},
+			warnings: []string{"test[go=1.23] -> build", "deploy -> test[go=1.22]"},
+		},
+		{
+			name:  "nothing selected",
+			only:  []string{"release"},
+			empty: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTestConfig(selectionGraph()...)
+			config.Only, config.Tags, config.Skip = test.only, test.tags, test.skip
+			config.WithDeps, config.WithDependents = test.deps, test.dependents
+			recorder := &warningRecorder{}
+			config.Logger.AddHook(recorder)
+
+			commands, err := selectCommands(config)
+			if test.empty {
+				var emptyErr *models.EmptySelectionError
+				if !errors.As(err, &emptyErr) {
+					t.Fatalf("selectCommands() = %v, want an EmptySelectionError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, command := range commands {
+				var dependencies []string
+				for _, dependency := range command.Dependencies {
+					dependencies = append(dependencies, strconv.Itoa(dependency))
+				}
+				entry := command.Name
+				if len(dependencies) > 0 {
+					entry += " <- " + strings.Join(dependencies, ",")
+				}
+				got = append(got, entry)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("selected %q, want %q", got, test.want)
+			}
+			if !slices.Equal(recorder.warnings, test.warnings) {
+				t.Errorf("warnings %q, want %q", recorder.warnings, test.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+	"github.com/unsubble/threadinator/internal/parsers"
+)
+
+type environment struct {
+	clear  bool
+	set    map[string]string
+	unset  []string
+	dir    string
+	inputs []string
+}
+
+func resolveEnvironment(config *models.Config, command *models.Command) (*environment, error) {
+	env := &environment{
+		clear: config.ClearEnv || command.ClearEnv,
+		set:   make(map[string]string),
+		dir:   config.Cwd,
+	}
+	if command.Cwd != "" {
+		env.dir = command.Cwd
+	}
+
+	maps.Copy(env.set, command.Vars)
+	if err := env.apply(config.EnvFiles, config.Env, nil); err != nil {
+		return nil, err
+	}
+	if err := env.apply(command.EnvFiles, command.Env, command.UnsetEnv); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (e *environment) apply(files []string, values map[string]string, unset []string) error {
+	for _, path := range files {
+		fileValues, err := parsers.ParseEnvFile(path)
+		if err != nil {
+			return err
+		}
+		maps.Copy(e.set, fileValues)
+	}
+
+	maps.Copy(e.set, values)
+
+	for _, name := range unset {
+		delete(e.set, name)
+		e.unset = append(e.unset, name)
+	}
+	return nil
+}
+
+func (e *environment) names() []string {
+	return slices.Sorted(maps.Keys(e.set))
+}
+
+func (e *environment) local() []string {
+	values := make(map[string]string)
+	if !e.clear {
+		for _, entry := range os.Environ() {
+			if name, value, found := strings.Cut(entry, "="); found {
+				values[name] = value
+			}
+		}
+	}
+
+	for _, name := range e.unset {
+		delete(values, name)
+	}
+	maps.Copy(values, e.set)
+
+	var entries []string
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		entries = append(entries, name+"="+values[name])
+	}
+	return entries
+}
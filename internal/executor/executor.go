@@ -1,14 +1,55 @@
 package executor
 
 import (
-	"sync"
+	"context"
+	"os"
 
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/cache"
 	"github.com/unsubble/threadinator/internal/models"
 )
 
-func Execute(config *models.Config) error {
-	config.Logger.Info("Starting execution process")
-	var wg sync.WaitGroup
+func Execute(ctx context.Context, config *models.Config) error {
+	return executeUntil(ctx, config, nil, nil)
+}
+
+func Resume(ctx context.Context, config *models.Config, saved *models.SavedRun) error {
+	config.Commands = saved.Commands
+	config.UsePipeline = saved.Pipeline
+	if config.ThreadCount <= 0 {
+		config.ThreadCount = saved.ThreadCount
+	}
+	config.Logger.WithField("resumed_from", saved.RunID).Info("Resuming run")
+	return executeUntil(ctx, config, saved, nil)
+}
+
+func RerunFailed(ctx context.Context, config *models.Config, report *models.SavedRun) error {
+	rerun := failedCommands(report, config.Logger)
+	if len(rerun.Commands) == 0 {
+		config.Logger.Info("No failed commands to rerun")
+		return nil
+	}
+	return Resume(ctx, config, rerun)
+}
+
+// executeUntil stops the run when ctx is cancelled: running commands are
+// interrupted, the others are skipped, and the run fails. Commands only run
+// while they hold one of slots, when it is given.
+func executeUntil(ctx context.Context, config *models.Config, resumed *models.SavedRun, slots chan struct{}) error {
+	jobs := newJobControl()
+	jobs.slots = slots
+	defer context.AfterFunc(ctx, jobs.stop)()
+
+	err := execute(config, resumed, jobs)
+	if err == nil && ctx.Err() != nil {
+		return models.NewInterruptedError()
+	}
+	return err
+}
+
+func execute(config *models.Config, resumed *models.SavedRun, jobs *jobControl) error {
+	config.RunID = newRunID()
+	config.Logger.WithField("run_id", config.RunID).Info("Starting execution process")
 	executionOrder, err := resolveExecutionOrder(config)
 	if err != nil {
 		config.Logger.Errorf("Execution order resolution failed: %v", err)
@@ -17,11 +58,72 @@ func Execute(config *models.Config) error {
 
 	errorChan := make(chan error, len(config.Commands))
 	poolChan := make(chan *Worker, config.ThreadCount)
+	config.Results = make([]*models.Result, len(config.Commands))
+	for index, command := range config.Commands {
+		config.Results[index] = models.NewResult(index, command)
+	}
+	restoreResults(config, resumed)
+
+	store := historyStore(config)
+	loadDurations(config, store)
+
+	run := newRunState(config, resumed, jobs)
+	defer run.streams.release()
+	run.recorder.save(config)
 
-	initializeWorkers(config.ThreadCount, poolChan, &wg, config)
-	scheduleCommands(config, executionOrder, poolChan, errorChan, &wg)
+	stopProgress := startProgress(run.progress, config.Progress)
+	initializeWorkers(config.ThreadCount, poolChan, config, run)
+	go scheduleCommands(config, run, executionOrder, poolChan, errorChan)
 
-	go finalizeExecution(&wg, errorChan, poolChan, config)
+	err = collectErrors(config, errorChan)
+	stopProgress()
+	recordHistory(config, store)
+	saveCache(config, run.cache)
+	if len(config.HostGroups) > 0 {
+		printHostMatrix(config)
+	}
+	printConcurrencySummary(os.Stdout, config)
+	run.recorder.writeReport(config)
+	if err != nil && run.recorder.keepsState() {
+		config.Logger.Errorf("Run %s failed, rerun the remaining commands with: %s resume %s", config.RunID, config.Name, config.RunID)
+	}
+	return err
+}
 
-	return collectErrors(config, errorChan)
+type runState struct {
+	streams   outputStreams
+	resources *resourcePool
+	progress  *progressTracker
+	status    *logrus.Logger
+	cache     *cache.Store
+	recorder  *runRecorder
+	restored  map[int]string
+	jobs      *jobControl
+	events    chan commandEvent
+}
+
+func newRunState(config *models.Config, resumed *models.SavedRun, jobs *jobControl) *runState {
+	recorder := newRunRecorder(config, resumed)
+	restored := make(map[int]string)
+	for index, result := range config.Results {
+		if !result.Restored {
+			continue
+		}
+		restored[index] = resumed.Results[index].Stdout
+		if recorder != nil {
+			restored[index] = recorder.state.Results[index].Stdout
+		}
+	}
+
+	return &runState{
+		streams:   newOutputStreams(config),
+		resources: newResourcePool(config),
+		progress:  newProgressTracker(config),
+		status:    statusLogger(config),
+		cache:     openCache(config),
+		recorder:  recorder,
+		restored:  restored,
+		jobs:      jobs,
+		events:    make(chan commandEvent, 2*len(config.Commands)),
+	}
 }
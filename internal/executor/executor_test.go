@@ -0,0 +1,246 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const schedulerDeadline = 10 * time.Second
+
+func fakeCommand(name, line string, dependencies ...int) *models.Command {
+	fields := strings.Fields(line)
+	return &models.Command{
+		Name:         name,
+		Command:      fields[0],
+		Args:         fields[1:],
+		Times:        1,
+		Dependencies: dependencies,
+		Backend:      models.BackendFake,
+	}
+}
+
+func newTestConfig(commands ...*models.Command) *models.Config {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return &models.Config{
+		Logger:      logger,
+		Commands:    commands,
+		ThreadCount: len(commands),
+		TimeUnit:    "s",
+		TimeoutInt:  10,
+		Timeout:     10 * time.Second,
+		Verbose:     true,
+	}
+}
+
+// runScheduler mirrors execute but drives scheduleCommands directly, failing the
+// test if the scheduler does not count every command down or leaves errorChan open.
+func runScheduler(t *testing.T, config *models.Config, resumed *models.SavedRun, jobs *jobControl) []error {
+	t.Helper()
+
+	order, err := resolveExecutionOrder(config)
+	if err != nil {
+		t.Fatalf("resolveExecutionOrder: %v", err)
+	}
+	config.Results = make([]*models.Result, len(config.Commands))
+	for index, command := range config.Commands {
+		config.Results[index] = models.NewResult(index, command)
+	}
+	restoreResults(config, resumed)
+
+	if jobs == nil {
+		jobs = newJobControl()
+	}
+	run := newRunState(config, resumed, jobs)
+	defer run.streams.release()
+
+	errorChan := make(chan error, len(config.Commands))
+	poolChan := make(chan *Worker, config.ThreadCount)
+	initializeWorkers(config.ThreadCount, poolChan, config, run)
+
+	done := make(chan struct{})
+	go func() {
+		scheduleCommands(config, run, order, poolChan, errorChan)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(schedulerDeadline):
+		t.Fatal("scheduler did not finish; remaining never reached 0")
+	}
+
+	var errs []error
+	for {
+		select {
+		case err, open := <-errorChan:
+			if !open {
+				saveCache(config, run.cache)
+				for index, result := range config.Results {
+					if !result.Finished() {
+						t.Errorf("command %d never finished", index)
+					}
+				}
+				return errs
+			}
+			errs = append(errs, err)
+		default:
+			t.Fatal("errorChan was not closed after the scheduler finished")
+		}
+	}
+}
+
+func statuses(config *models.Config) []string {
+	var statuses []string
+	for _, result := range config.Results {
+		statuses = append(statuses, result.Status)
+	}
+	return statuses
+}
+
+func TestExecuteFakeBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []*models.Command
+		timeout  time.Duration
+		statuses []string
+		failed   int
+	}{
+		{
+			name: "dependency order",
+			commands: []*models.Command{
+				fakeCommand("a", "sleep 30ms"),
+				fakeCommand("b", "sleep 10ms", 0),
+				fakeCommand("c", "echo c", 1),
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSuccess, models.RunStatusSuccess},
+		},
+		{
+			name: "failure propagation",
+			commands: []*models.Command{
+				fakeCommand("a", "false"),
+				fakeCommand("b", "echo b", 0),
+				fakeCommand("c", "exit 3"),
+			},
+			statuses: []string{models.RunStatusFailed, models.RunStatusSuccess, models.RunStatusFailed},
+			failed:   2,
+		},
+		{
+			name: "timeout",
+			commands: []*models.Command{
+				fakeCommand("a", "sleep 10s"),
+				fakeCommand("b", "echo b", 0),
+			},
+			timeout:  50 * time.Millisecond,
+			statuses: []string{models.RunStatusTimeout, models.RunStatusSuccess},
+			failed:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTestConfig(test.commands...)
+			if test.timeout > 0 {
+				config.Timeout = test.timeout
+			}
+
+			started := time.Now()
+			errs := runScheduler(t, config, nil, nil)
+			if len(errs) != test.failed {
+				t.Errorf("got %d errors %v, want %d", len(errs), errs, test.failed)
+			}
+			if got := statuses(config); strings.Join(got, ",") != strings.Join(test.statuses, ",") {
+				t.Errorf("statuses = %v, want %v", got, test.statuses)
+			}
+			if test.timeout > 0 && time.Since(started) > 5*time.Second {
+				t.Errorf("timed out command was not stopped promptly")
+			}
+
+			for index, command := range config.Commands {
+				for _, dependency := range command.Dependencies {
+					if config.Results[index].Start.Before(config.Results[dependency].End) {
+						t.Errorf("command %d started before its dependency %d finished", index, dependency)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteFakeBackendReturnsExecutionError(t *testing.T) {
+	config := newTestConfig(fakeCommand("a", "false"), fakeCommand("b", "echo b"))
+	config.RecordHistory = false
+
+	err := Execute(context.Background(), config)
+	var executionErr *models.ExecutionError
+	if !errors.As(err, &executionErr) || executionErr.Failed != 1 || executionErr.Total != 2 {
+		t.Fatalf("Execute() = %v, want 1 of 2 commands failed", err)
+	}
+}
+
+func TestFakeBackendCancellation(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration
+	}{
+		{name: "while running", delay: 50 * time.Millisecond},
+		{name: "before starting", delay: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTestConfig(fakeCommand("a", "sleep 10s"), fakeCommand("b", "sleep 10s", 0))
+			jobs := newJobControl()
+			if test.delay == 0 {
+				jobs.cancel(0)
+				jobs.cancel(1)
+			} else {
+				time.AfterFunc(test.delay, func() {
+					jobs.cancel(1)
+					jobs.cancel(0)
+				})
+			}
+
+			started := time.Now()
+			if errs := runScheduler(t, config, nil, jobs); len(errs) != 0 {
+				t.Errorf("cancelled commands reported errors: %v", errs)
+			}
+			if elapsed := time.Since(started); elapsed > 5*time.Second {
+				t.Errorf("cancellation took %v", elapsed)
+			}
+			for index, result := range config.Results {
+				if result.Status != models.RunStatusSkipped {
+					t.Errorf("command %d status = %s, want skipped", index, result.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteStopsOnCancellation(t *testing.T) {
+	poll := fakeCommand("poll", "echo poll")
+	poll.Interval = &models.Interval{Every: 10 * time.Millisecond}
+	config := newTestConfig(poll, fakeCommand("after", "echo after", 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	err := Execute(ctx, config)
+	var interruptedErr *models.InterruptedError
+	if !errors.As(err, &interruptedErr) {
+		t.Fatalf("Execute() = %v, want an interrupted run", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("stopping the interval took %v", elapsed)
+	}
+	if got := statuses(config); strings.Join(got, ",") != "skipped,skipped" {
+		t.Errorf("statuses = %v, want both skipped", got)
+	}
+}
@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type fakeExecutor struct {
+	processStreams
+	command *models.Command
+	cancel  context.CancelFunc
+}
+
+func newFakeExecutor(command *models.Command) *fakeExecutor {
+	return &fakeExecutor{
+		processStreams: newProcessStreams(),
+		command:        command,
+	}
+}
+
+func (e *fakeExecutor) Start(ctx context.Context) error {
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.run(ctx)
+	}()
+	go func() {
+		select {
+		case err := <-done:
+			e.finish(err)
+		case <-ctx.Done():
+			e.finish(ctx.Err())
+		}
+	}()
+	return nil
+}
+
+func (e *fakeExecutor) run(ctx context.Context) error {
+	line := strings.Join(append([]string{e.command.Command}, e.command.Args...), " ")
+	if _, err := fmt.Fprintln(e.stdoutWriter, line); err != nil {
+		return err
+	}
+	if e.stdin != nil {
+		if _, err := io.Copy(e.stdoutWriter, e.stdin); err != nil {
+			return err
+		}
+	}
+
+	switch e.command.Command {
+	case "sleep":
+		if len(e.command.Args) == 0 {
+			return nil
+		}
+		duration, err := time.ParseDuration(e.command.Args[0])
+		if err != nil {
+			return err
+		}
+		select {
+		case <-time.After(duration):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case "false":
+		return fmt.Errorf("exit status 1")
+	case "exit":
+		if len(e.command.Args) > 0 {
+			if code, err := strconv.Atoi(e.command.Args[0]); err == nil && code != 0 {
+				return fmt.Errorf("exit status %d", code)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *fakeExecutor) Signal(sig os.Signal) error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	return nil
+}
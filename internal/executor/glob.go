@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func expandGlobs(dir string, patterns []string) ([]string, error) {
+	var files []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+
+		matches, err := matchGlob(pattern)
+		if err != nil {
+			return nil, models.NewOptionError("inputs", pattern, "invalid glob pattern")
+		}
+		for _, match := range matches {
+			found, err := regularFiles(match)
+			if err != nil {
+				return nil, models.NewFileOpenError(match, err)
+			}
+			files = append(files, found...)
+		}
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+func matchGlob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(pattern)
+	}
+
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	root := 0
+	for root < len(segments) && !strings.ContainsAny(segments[root], "*?[") {
+		root++
+	}
+	rootDir := filepath.FromSlash(strings.Join(segments[:root], "/"))
+	if rootDir == "" && filepath.IsAbs(pattern) {
+		rootDir = string(filepath.Separator)
+	} else if rootDir == "" {
+		rootDir = "."
+	}
+
+	var matches []string
+	err := filepath.WalkDir(rootDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		relative, err := filepath.Rel(rootDir, path)
+		if err != nil || relative == "." {
+			return nil
+		}
+		matched, err := matchSegments(segments[root:], strings.Split(filepath.ToSlash(relative), "/"))
+		if matched {
+			matches = append(matches, path)
+		}
+		return err
+	})
+	return matches, err
+}
+
+func matchSegments(pattern, path []string) (bool, error) {
+	if len(pattern) == 0 {
+		return len(path) == 0, nil
+	}
+
+	if pattern[0] == "**" {
+		for skip := 0; skip <= len(path); skip++ {
+			if matched, err := matchSegments(pattern[1:], path[skip:]); matched || err != nil {
+				return matched, err
+			}
+		}
+		return false, nil
+	}
+
+	if len(path) == 0 {
+		return false, nil
+	}
+	matched, err := filepath.Match(pattern[0], path[0])
+	if !matched || err != nil {
+		return false, err
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
+
+func regularFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.Type().IsRegular() {
+			files = append(files, file)
+		}
+		return err
+	})
+	return files, err
+}
@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestExpandGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"a.txt", "b.go", "sub/c.txt", "sub/deep/d.txt", "sub/deep/e.go"} {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeInput(t, path, file)
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{name: "plain glob", patterns: []string{"*.txt"}, want: []string{"a.txt"}},
+		{name: "leading double star", patterns: []string{"**/*.txt"}, want: []string{"a.txt", "sub/c.txt", "sub/deep/d.txt"}},
+		{name: "double star inside", patterns: []string{"sub/**/*.go"}, want: []string{"sub/deep/e.go"}},
+		{name: "directory", patterns: []string{"sub/deep"}, want: []string{"sub/deep/d.txt", "sub/deep/e.go"}},
+		{name: "overlapping patterns", patterns: []string{"**/*.go", "b.go"}, want: []string{"b.go", "sub/deep/e.go"}},
+		{name: "no match", patterns: []string{"**/*.md"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var want []string
+			for _, file := range test.want {
+				want = append(want, filepath.Join(dir, file))
+			}
+			got, err := expandGlobs(dir, test.patterns)
+			if err != nil || !slices.Equal(got, want) {
+				t.Fatalf("expandGlobs(%q) = %q, %v; want %q", test.patterns, got, err, want)
+			}
+		})
+	}
+}
+
+func TestExpandGlobsRelativeToWorkingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeInput(t, filepath.Join(dir, "a.txt"), "a")
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	got, err := expandGlobs("", []string{"**/*.txt"})
+	if err != nil || !slices.Equal(got, []string{"a.txt"}) {
+		t.Fatalf("expandGlobs(\"**/*.txt\") = %q, %v; want the file in the working directory", got, err)
+	}
+}
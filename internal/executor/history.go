@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"github.com/unsubble/threadinator/internal/history"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const historyWindow = 20
+
+func historyStore(config *models.Config) *history.Store {
+	if !config.RecordHistory {
+		return nil
+	}
+	return history.NewStore(historyPath(config))
+}
+
+func historyPath(config *models.Config) string {
+	if config.HistoryFile != "" {
+		return config.HistoryFile
+	}
+	return history.DefaultPath()
+}
+
+func loadDurations(config *models.Config, store *history.Store) {
+	if store == nil {
+		return
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		config.Logger.WithError(err).Warn("Failed to load run history")
+		return
+	}
+	config.Durations = history.Estimates(records, historyWindow)
+}
+
+func recordHistory(config *models.Config, store *history.Store) {
+	if store == nil {
+		return
+	}
+
+	var records []*models.RunRecord
+	for _, result := range config.Results {
+		if !result.Finished() || result.Restored {
+			continue
+		}
+		record := &models.RunRecord{
+			RunID:    config.RunID,
+			Job:      result.Command.Key(),
+			Start:    result.Start,
+			End:      result.End,
+			Duration: result.End.Sub(result.Start),
+			Status:   result.Status,
+		}
+		if result.Err != nil {
+			record.Error = result.Err.Error()
+		}
+		records = append(records, record)
+	}
+
+	if err := store.Append(records...); err != nil {
+		config.Logger.WithError(err).Warn("Failed to record run history")
+	}
+}
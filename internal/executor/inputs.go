@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"bufio"
+	"io"
+	"maps"
+	"os"
+	"strconv"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const (
+	inputFileEnv  = "THREADINATOR_INPUT_"
+	inputCountEnv = "THREADINATOR_INPUTS"
+)
+
+func composeStdin(mode string, parents []io.Reader) io.Reader {
+	if len(parents) == 0 || mode == models.StdinFiles {
+		return nil
+	}
+	if mode == models.StdinInterleave {
+		return interleaveLines(parents)
+	}
+	return io.MultiReader(parents...)
+}
+
+func interleaveLines(parents []io.Reader) io.Reader {
+	reader, writer := io.Pipe()
+	go func() {
+		var remaining []*bufio.Reader
+		for _, parent := range parents {
+			remaining = append(remaining, bufio.NewReader(parent))
+		}
+
+		for len(remaining) > 0 {
+			next := remaining[:0]
+			for _, parent := range remaining {
+				line, err := parent.ReadBytes('\n')
+				if len(line) > 0 && line[len(line)-1] != '\n' {
+					line = append(line, '\n')
+				}
+				if _, writeErr := writer.Write(line); writeErr != nil {
+					return
+				}
+				if err != nil && err != io.EOF {
+					writer.CloseWithError(err)
+					return
+				}
+				if err == nil {
+					next = append(next, parent)
+				}
+			}
+			remaining = next
+		}
+		writer.Close()
+	}()
+	return reader
+}
+
+func prepareInputFiles(command *models.Command, parents []io.Reader) ([]string, error) {
+	if command.Stdin != models.StdinFiles || len(parents) == 0 {
+		return nil, nil
+	}
+
+	env := make(map[string]string)
+	maps.Copy(env, command.Env)
+
+	var paths []string
+	for i, output := range parents {
+		path, err := writeInputFile(output)
+		if err != nil {
+			removeFiles(paths)
+			return nil, err
+		}
+		paths = append(paths, path)
+		env[inputFileEnv+strconv.Itoa(i)] = path
+	}
+	env[inputCountEnv] = strconv.Itoa(len(paths))
+	command.Env = env
+
+	return paths, nil
+}
+
+func writeInputFile(output io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "threadinator-input-*")
+	if err != nil {
+		return "", models.NewFileOpenError(os.TempDir(), err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, output); err != nil {
+		os.Remove(file.Name())
+		return "", models.NewFileOpenError(file.Name(), err)
+	}
+	return file.Name(), nil
+}
+
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
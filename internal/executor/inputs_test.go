@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func readers(outputs ...string) []io.Reader {
+	var parents []io.Reader
+	for _, output := range outputs {
+		parents = append(parents, strings.NewReader(output))
+	}
+	return parents
+}
+
+func TestComposeStdin(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		outputs []string
+		want    string
+	}{
+		{name: "concat", mode: models.StdinConcat, outputs: []string{"a1\na2\n", "b1\n"}, want: "a1\na2\nb1\n"},
+		{name: "interleave", mode: models.StdinInterleave, outputs: []string{"a1\na2\na3\n", "b1\n", "c1\nc2"}, want: "a1\nb1\nc1\na2\nc2\na3\n"},
+		{name: "interleave empty parent", mode: models.StdinInterleave, outputs: []string{"", "b1\nb2\n"}, want: "b1\nb2\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := io.ReadAll(composeStdin(test.mode, readers(test.outputs...)))
+			if err != nil || string(got) != test.want {
+				t.Fatalf("stdin = %q, %v; want %q", got, err, test.want)
+			}
+		})
+	}
+
+	if stdin := composeStdin(models.StdinFiles, readers("a\n")); stdin != nil {
+		t.Error("files mode also feeds stdin")
+	}
+}
+
+func TestInterleaveReportsParentErrors(t *testing.T) {
+	broken := errors.New("parent output lost")
+	parents := []io.Reader{
+		io.MultiReader(strings.NewReader("a1\n"), iotest.ErrReader(broken)),
+		strings.NewReader("b1\nb2\n"),
+	}
+
+	got, err := io.ReadAll(interleaveLines(parents))
+	if !errors.Is(err, broken) {
+		t.Fatalf("reading truncated input = %q, %v; want %v", got, err, broken)
+	}
+}
+
+func TestPrepareInputFiles(t *testing.T) {
+	command := &models.Command{Stdin: models.StdinFiles, Env: map[string]string{"MODE": "merge"}}
+	paths, err := prepareInputFiles(command, readers("first\n", "second\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeFiles(paths)
+
+	if len(paths) != 2 || command.Env[inputCountEnv] != "2" || command.Env["MODE"] != "merge" {
+		t.Fatalf("paths %q with env %v", paths, command.Env)
+	}
+	for i, want := range []string{"first\n", "second\n"} {
+		path := command.Env[inputFileEnv+strconv.Itoa(i)]
+		if path != paths[i] {
+			t.Errorf("%s%d = %q, want %q", inputFileEnv, i, path, paths[i])
+		}
+		if data, err := os.ReadFile(path); err != nil || string(data) != want {
+			t.Errorf("input file %d = %q, %v; want %q", i, data, err, want)
+		}
+	}
+
+	removeFiles(paths)
+	for _, path := range paths {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", path)
+		}
+	}
+}
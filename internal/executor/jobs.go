@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type jobControl struct {
+	mu        sync.Mutex
+	stopped   bool
+	cancelled map[int]bool
+	running   map[int]context.CancelFunc
+	slots     chan struct{}
+}
+
+func newJobControl() *jobControl {
+	return &jobControl{
+		cancelled: make(map[int]bool),
+		running:   make(map[int]context.CancelFunc),
+	}
+}
+
+func (c *jobControl) begin(index int) (context.Context, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return nil, models.NewSkipError("run interrupted")
+	}
+	if c.cancelled[index] {
+		return nil, models.NewSkipError("cancelled by a newer change")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.running[index] = cancel
+	return ctx, nil
+}
+
+func (c *jobControl) end(index int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, has := c.running[index]; has {
+		cancel()
+		delete(c.running, index)
+	}
+}
+
+func (c *jobControl) cancel(index int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelled[index] = true
+	if cancel, has := c.running[index]; has {
+		cancel()
+	}
+}
+
+// stop cancels every running command and skips the ones not started yet.
+func (c *jobControl) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	for _, cancel := range c.running {
+		cancel()
+	}
+}
+
+// acquire waits for one of the slots shared with other runs, if any, calling
+// waiting first when none is free.
+func (c *jobControl) acquire(ctx context.Context, waiting func()) bool {
+	if c.slots == nil {
+		return true
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	waiting()
+	select {
+	case c.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c *jobControl) release() {
+	if c.slots != nil {
+		<-c.slots
+	}
+}
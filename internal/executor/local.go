@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type localExecutor struct {
+	processStreams
+	command *models.Command
+	env     []string
+	dir     string
+	inputs  []string
+	cmd     *exec.Cmd
+}
+
+func newLocalExecutor(command *models.Command, env []string, dir string, inputs []string) *localExecutor {
+	return &localExecutor{
+		processStreams: newProcessStreams(),
+		command:        command,
+		env:            env,
+		dir:            dir,
+		inputs:         inputs,
+	}
+}
+
+func (e *localExecutor) Start(ctx context.Context) error {
+	e.cmd = exec.Command(e.command.Command, e.command.Args...)
+	e.cmd.Env = e.env
+	e.cmd.Dir = e.dir
+	e.cmd.Stdout = e.stdoutWriter
+	e.cmd.Stderr = e.stderrWriter
+
+	for _, path := range e.inputs {
+		file, err := os.Open(path)
+		if err != nil {
+			return models.NewFileOpenError(path, err)
+		}
+		defer file.Close()
+		e.cmd.ExtraFiles = append(e.cmd.ExtraFiles, file)
+	}
+
+	if e.stdin != nil {
+		stdin, err := e.cmd.StdinPipe()
+		if err != nil {
+			return models.NewCommandError(e.command.Command, err.Error())
+		}
+		go feedStdin(stdin, e.stdin)
+	}
+
+	if err := e.cmd.Start(); err != nil {
+		return models.NewCommandError(e.command.Command, err.Error())
+	}
+
+	go func() {
+		e.finish(e.cmd.Wait())
+	}()
+	return nil
+}
+
+func (e *localExecutor) Signal(sig os.Signal) error {
+	if e.cmd == nil || e.cmd.Process == nil {
+		return nil
+	}
+	return e.cmd.Process.Signal(sig)
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+	"github.com/unsubble/threadinator/internal/parsers"
+)
+
+const (
+	outputFileEnv = "THREADINATOR_OUTPUT"
+	stdoutOutput  = "stdout"
+)
+
+func capturesStdout(command *models.Command) bool {
+	return slices.Contains(command.Outputs, stdoutOutput)
+}
+
+func usesOutputFile(command *models.Command) bool {
+	return slices.ContainsFunc(command.Outputs, func(name string) bool { return name != stdoutOutput })
+}
+
+func prepareOutputFile(command *models.Command) (string, error) {
+	if !usesOutputFile(command) {
+		return "", nil
+	}
+
+	file, err := os.CreateTemp("", "threadinator-output-*")
+	if err != nil {
+		return "", models.NewFileOpenError(os.TempDir(), err)
+	}
+	file.Close()
+
+	env := make(map[string]string)
+	maps.Copy(env, command.Env)
+	env[outputFileEnv] = file.Name()
+	command.Env = env
+
+	return file.Name(), nil
+}
+
+func collectOutputs(command *models.Command, stdout, outputFile string) (map[string]string, error) {
+	fileValues := make(map[string]string)
+	if outputFile != "" {
+		values, err := parsers.ParseEnvFile(outputFile)
+		if err != nil {
+			return nil, err
+		}
+		fileValues = values
+	}
+
+	outputs := make(map[string]string)
+	for _, name := range command.Outputs {
+		if name == stdoutOutput {
+			outputs[name] = strings.TrimSpace(stdout)
+		} else if value, has := fileValues[name]; has {
+			outputs[name] = value
+		}
+	}
+	return outputs, nil
+}
+
+func outputVariables(results []*models.Result) map[string]string {
+	vars := make(map[string]string)
+	for _, result := range results {
+		if result == nil || result.Command.Name == "" || !result.Finished() {
+			continue
+		}
+		for name, value := range result.Outputs {
+			vars["jobs."+result.Command.Name+".outputs."+name] = value
+		}
+	}
+	return vars
+}
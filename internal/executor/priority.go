@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const defaultEstimate = time.Second
+
+// estimateDuration uses the durations loadDurations read from the run history
+// before scheduling starts; without history every command counts the same.
+func estimateDuration(config *models.Config, command *models.Command) time.Duration {
+	if estimate, has := config.Durations[command.Key()]; has && estimate > 0 {
+		return estimate
+	}
+	return defaultEstimate
+}
+
+func criticalPaths(config *models.Config, executionOrder []int) []time.Duration {
+	dependents := make(map[int][]int)
+	for index, command := range config.Commands {
+		for _, dependency := range command.Dependencies {
+			dependents[dependency] = append(dependents[dependency], index)
+		}
+	}
+
+	paths := make([]time.Duration, len(config.Commands))
+	for _, index := range slices.Backward(executionOrder) {
+		var longest time.Duration
+		for _, dependent := range dependents[index] {
+			longest = max(longest, paths[dependent])
+		}
+		paths[index] = estimateDuration(config, config.Commands[index]) + longest
+	}
+	return paths
+}
+
+func prioritizeCommands(config *models.Config, executionOrder []int) []int {
+	paths := criticalPaths(config, executionOrder)
+	ordered := slices.Clone(executionOrder)
+	slices.SortStableFunc(ordered, func(a, b int) int {
+		return cmp.Or(
+			cmp.Compare(config.Commands[b].Priority, config.Commands[a].Priority),
+			cmp.Compare(paths[b], paths[a]),
+		)
+	})
+
+	for _, index := range ordered {
+		config.Logger.WithFields(logrus.Fields{
+			"index":         index,
+			"priority":      config.Commands[index].Priority,
+			"critical_path": paths[index],
+		}).Debug("Command ready order")
+	}
+	return ordered
+}
@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestPrioritizeCommandsUsesHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		durations map[string]time.Duration
+		want      []int
+	}{
+		{name: "without history the longer chain goes first", want: []int{1, 0, 2}},
+		{name: "recorded durations outweigh chain length", durations: map[string]time.Duration{"slow": 10 * time.Second}, want: []int{0, 1, 2}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTestConfig(fakeCommand("slow", "sleep 10s"), fakeCommand("build", "make"), fakeCommand("test", "make test", 1))
+			config.RecordHistory = true
+			config.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+
+			store := historyStore(config)
+			start := time.Now()
+			for job, duration := range test.durations {
+				record := &models.RunRecord{RunID: "earlier", Job: job, Start: start, End: start.Add(duration), Duration: duration, Status: models.RunStatusSuccess}
+				if err := store.Append(record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			loadDurations(config, store)
+
+			order, err := resolveExecutionOrder(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := prioritizeCommands(config, order); !slices.Equal(got, test.want) {
+				t.Fatalf("ready order = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package executor
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type progressTracker struct {
+	mu       sync.Mutex
+	config   *models.Config
+	start    time.Time
+	running  map[int]time.Time
+	done     int
+	restored int
+	failed   int
+	busy     time.Duration
+}
+
+type progressSnapshot struct {
+	done       int
+	running    int
+	pending    int
+	failed     int
+	throughput float64
+	eta        time.Duration
+}
+
+func newProgressTracker(config *models.Config) *progressTracker {
+	tracker := &progressTracker{
+		config:  config,
+		start:   time.Now(),
+		running: make(map[int]time.Time),
+	}
+	for _, result := range config.Results {
+		if result.Restored {
+			tracker.restored++
+		}
+	}
+	tracker.done = tracker.restored
+	return tracker
+}
+
+func (p *progressTracker) started(index int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, has := p.running[index]; !has {
+		p.running[index] = time.Now()
+	}
+}
+
+func (p *progressTracker) finished(index int, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if start, has := p.running[index]; has {
+		p.busy += time.Since(start)
+		delete(p.running, index)
+	}
+	p.done++
+	if status == models.RunStatusFailed || status == models.RunStatusTimeout {
+		p.failed++
+	}
+}
+
+func (p *progressTracker) snapshot() progressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := len(p.config.Commands)
+	snapshot := progressSnapshot{
+		done:    p.done,
+		running: len(p.running),
+		pending: total - p.done - len(p.running),
+		failed:  p.failed,
+		eta:     -1,
+	}
+
+	elapsed := time.Since(p.start)
+	if elapsed > 0 {
+		snapshot.throughput = float64(p.done-p.restored) / elapsed.Seconds()
+	}
+	if remaining := total - p.done; remaining == 0 {
+		snapshot.eta = 0
+	} else if work, known := p.remainingWork(); known {
+		slots := min(max(p.config.ThreadCount, 1), remaining)
+		snapshot.eta = work / time.Duration(slots)
+	} else if snapshot.throughput > 0 {
+		snapshot.eta = time.Duration(float64(remaining) / snapshot.throughput * float64(time.Second))
+	}
+	return snapshot
+}
+
+func (p *progressTracker) remainingWork() (time.Duration, bool) {
+	var average time.Duration
+	if completed := p.done - p.restored; completed > 0 {
+		average = p.busy / time.Duration(completed)
+	}
+
+	var work time.Duration
+	for index, command := range p.config.Commands {
+		if p.config.Results[index].Finished() {
+			continue
+		}
+		estimate, has := p.config.Durations[command.Key()]
+		if !has {
+			if average == 0 {
+				return 0, false
+			}
+			estimate = average
+		}
+		if start, running := p.running[index]; running {
+			estimate = max(estimate-time.Since(start), 0)
+		}
+		work += estimate
+	}
+	return work, true
+}
+
+func startProgress(tracker *progressTracker, interval time.Duration) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	logger := statusLogger(tracker.config)
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				printProgress(logger, tracker.snapshot())
+			case <-stop:
+				printProgress(logger, tracker.snapshot())
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-stopped
+	}
+}
+
+func printProgress(logger *logrus.Logger, snapshot progressSnapshot) {
+	eta := "unknown"
+	if snapshot.eta >= 0 {
+		eta = snapshot.eta.Round(time.Second).String()
+	}
+	logger.WithFields(logrus.Fields{
+		"done":       snapshot.done,
+		"running":    snapshot.running,
+		"pending":    snapshot.pending,
+		"failed":     snapshot.failed,
+		"throughput": math.Round(snapshot.throughput*100) / 100,
+		"eta":        eta,
+	}).Info("Progress")
+}
+
+// statusLogger logs status lines the user asked for, like --progress and watch
+// rounds, in the configured log format but whatever the log level.
+func statusLogger(config *models.Config) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(config.Logger.Out)
+	logger.SetFormatter(config.Logger.Formatter)
+	logger.ReplaceHooks(config.Logger.Hooks)
+	logger.SetLevel(max(config.Logger.GetLevel(), logrus.InfoLevel))
+	return logger
+}
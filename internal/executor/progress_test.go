@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestProgressFollowsLogFormat(t *testing.T) {
+	config := newTestConfig(fakeCommand("a", "echo a"), fakeCommand("b", "false"))
+	var output bytes.Buffer
+	config.Logger.SetOutput(&output)
+	config.Logger.SetFormatter(&logrus.JSONFormatter{})
+	config.Logger.SetLevel(logrus.ErrorLevel)
+	config.Results = make([]*models.Result, len(config.Commands))
+	for index, command := range config.Commands {
+		config.Results[index] = models.NewResult(index, command)
+	}
+
+	tracker := newProgressTracker(config)
+	tracker.started(0)
+	tracker.finished(0, models.RunStatusSuccess)
+	startProgress(tracker, schedulerDeadline)()
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("progress printed %q, want one line when stopped", lines)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("progress line %q is not JSON: %v", lines[0], err)
+	}
+	if entry["msg"] != "Progress" || entry["level"] != "info" || entry["done"] != 1.0 || entry["pending"] != 1.0 {
+		t.Fatalf("progress entry = %v", entry)
+	}
+}
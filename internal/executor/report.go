@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func printHostMatrix(config *models.Config) {
+	var hosts []string
+	rows := make(map[string][]int)
+	for index, command := range config.Commands {
+		if command.Host == nil {
+			continue
+		}
+		host := command.Host.String()
+		if _, has := rows[host]; !has {
+			hosts = append(hosts, host)
+		}
+		rows[host] = append(rows[host], index)
+	}
+
+	if len(hosts) == 0 {
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := []string{"HOST"}
+	for column, index := range rows[hosts[0]] {
+		header = append(header, fmt.Sprintf("#%d %s", column, commandLabel(config.Commands[index])))
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+
+	for _, host := range hosts {
+		cells := []string{host}
+		for _, index := range rows[host] {
+			cells = append(cells, resultStatus(config.Results[index]))
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	writer.Flush()
+}
+
+func printConcurrencySummary(output io.Writer, config *models.Config) {
+	limits := groupLimits(config.Commands)
+	if len(limits) == 0 {
+		return
+	}
+
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "GROUP\tMAX\tCOMMANDS\tWAITED\tLONGEST WAIT")
+	for _, name := range slices.Sorted(maps.Keys(limits)) {
+		var count int
+		var total, longest time.Duration
+		for index, command := range config.Commands {
+			if _, member := concurrencyLimits(command)[name]; !member {
+				continue
+			}
+			waited := config.Results[index].Waited
+			count++
+			total += waited
+			longest = max(longest, waited)
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\t%s\n", name, limits[name], count,
+			total.Round(time.Millisecond), longest.Round(time.Millisecond))
+	}
+	writer.Flush()
+}
+
+func resultStatus(result *models.Result) string {
+	if result == nil || result.Status == "" {
+		return "pending"
+	}
+	return result.Status
+}
+
+func commandLabel(command *models.Command) string {
+	if command.Name != "" {
+		return command.Name
+	}
+	return command.Command
+}
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"maps"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const groupResourcePrefix = "group:"
+
+type resourcePool struct {
+	capacity models.Resources
+	used     models.Resources
+}
+
+func newResourcePool(config *models.Config) *resourcePool {
+	capacity := models.Resources{models.CPUResource: models.Quantity(runtime.NumCPU())}
+	maps.Copy(capacity, config.Resources)
+	for name, limit := range groupLimits(config.Commands) {
+		capacity[groupResourcePrefix+name] = models.Quantity(limit)
+	}
+
+	unlimited := make(map[string]bool)
+	for _, command := range config.Commands {
+		for name := range command.Resources {
+			if _, has := capacity[name]; !has && !unlimited[name] {
+				unlimited[name] = true
+				config.Logger.WithFields(logrus.Fields{"resource": name, "command": command.Key()}).Warn("Resource has no declared capacity and is not limited")
+			}
+		}
+	}
+
+	return &resourcePool{
+		capacity: capacity,
+		used:     make(models.Resources),
+	}
+}
+
+func concurrencyLimits(command *models.Command) map[string]int {
+	limits := make(map[string]int)
+	for _, lock := range command.Locks {
+		limits[lock] = 1
+	}
+	if group := command.Group; group != nil {
+		if _, has := limits[group.Name]; !has {
+			limits[group.Name] = group.Max
+		}
+	}
+	return limits
+}
+
+func groupLimits(commands []*models.Command) map[string]int {
+	limits := make(map[string]int)
+	for _, command := range commands {
+		for name, limit := range concurrencyLimits(command) {
+			if current, has := limits[name]; !has || current == 0 || limit > 0 && limit < current {
+				limits[name] = limit
+			}
+		}
+	}
+	for name, limit := range limits {
+		if limit == 0 {
+			limits[name] = 1
+		}
+	}
+	return limits
+}
+
+func commandRequest(command *models.Command) models.Resources {
+	request := make(models.Resources)
+	maps.Copy(request, command.Resources)
+	for name := range concurrencyLimits(command) {
+		request[groupResourcePrefix+name] = 1
+	}
+	return request
+}
+
+func (p *resourcePool) grant(request models.Resources) models.Resources {
+	granted := make(models.Resources, len(request))
+	for name, amount := range request {
+		if limit, has := p.capacity[name]; has && amount > limit {
+			amount = limit
+		}
+		granted[name] = amount
+	}
+	return granted
+}
+
+func (p *resourcePool) fits(granted models.Resources) bool {
+	for name, amount := range granted {
+		if limit, has := p.capacity[name]; has && p.used[name]+amount > limit {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *resourcePool) tryAcquire(request models.Resources) (models.Resources, bool) {
+	if len(request) == 0 {
+		return nil, true
+	}
+
+	granted := p.grant(request)
+	if !p.fits(granted) {
+		return nil, false
+	}
+	for name, amount := range granted {
+		p.used[name] += amount
+	}
+	return granted, true
+}
+
+func (p *resourcePool) release(granted models.Resources) {
+	for name, amount := range granted {
+		p.used[name] -= amount
+	}
+}
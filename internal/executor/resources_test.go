@@ -0,0 +1,143 @@
+package executor
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestGroupLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []*models.Command
+		want     map[string]int
+	}{
+		{name: "no groups", commands: []*models.Command{{}}, want: map[string]int{}},
+		{name: "lock", commands: []*models.Command{{Locks: []string{"db"}}}, want: map[string]int{"db": 1}},
+		{name: "group", commands: []*models.Command{{Group: &models.ConcurrencyGroup{Name: "deploy", Max: 3}}}, want: map[string]int{"deploy": 3}},
+		{name: "smallest max wins", commands: []*models.Command{
+			{Group: &models.ConcurrencyGroup{Name: "deploy", Max: 3}},
+			{Group: &models.ConcurrencyGroup{Name: "deploy", Max: 2}},
+			{Group: &models.ConcurrencyGroup{Name: "deploy"}},
+		}, want: map[string]int{"deploy": 2}},
+		{name: "group without max", commands: []*models.Command{{Group: &models.ConcurrencyGroup{Name: "deploy"}}}, want: map[string]int{"deploy": 1}},
+		{name: "lock of the same name", commands: []*models.Command{
+			{Group: &models.ConcurrencyGroup{Name: "db", Max: 4}},
+			{Locks: []string{"db"}},
+		}, want: map[string]int{"db": 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := groupLimits(test.commands); !maps.Equal(got, test.want) {
+				t.Fatalf("groupLimits() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// maxOverlap returns the largest number of the given commands that ran at once.
+func maxOverlap(config *models.Config, indexes []int) int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	var edges []edge
+	for _, index := range indexes {
+		result := config.Results[index]
+		edges = append(edges, edge{result.Start, 1}, edge{result.End, -1})
+	}
+	slices.SortFunc(edges, func(a, b edge) int {
+		if c := a.at.Compare(b.at); c != 0 {
+			return c
+		}
+		return a.delta - b.delta
+	})
+
+	running, most := 0, 0
+	for _, edge := range edges {
+		running += edge.delta
+		most = max(most, running)
+	}
+	return most
+}
+
+func TestSchedulerLimitsConcurrency(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  func(command *models.Command)
+		most   int
+		others bool
+	}{
+		{name: "lock", limit: func(command *models.Command) { command.Locks = []string{"db"} }, most: 1},
+		{name: "group max=2", limit: func(command *models.Command) { command.Group = &models.ConcurrencyGroup{Name: "deploy", Max: 2} }, most: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var commands []*models.Command
+			var limited []int
+			for index := range 6 {
+				command := fakeCommand("job", "sleep 30ms")
+				if index%3 != 2 {
+					test.limit(command)
+					limited = append(limited, index)
+				}
+				commands = append(commands, command)
+			}
+			config := newTestConfig(commands...)
+
+			if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+				t.Fatalf("run failed: %v", errs)
+			}
+			if got := maxOverlap(config, limited); got != test.most {
+				t.Errorf("%d limited commands ran at once, want %d", got, test.most)
+			}
+			if got := maxOverlap(config, []int{2, 5}); got != 2 {
+				t.Errorf("unlimited commands were held back: %d ran at once", got)
+			}
+		})
+	}
+}
+
+func TestWaitedExcludesWorkerSlots(t *testing.T) {
+	holder := fakeCommand("holder", "sleep 100ms")
+	holder.Locks = []string{"db"}
+	holder.Priority = 3
+	waiter := fakeCommand("waiter", "echo waiter")
+	waiter.Locks = []string{"db"}
+	waiter.Priority = 2
+	long := fakeCommand("long", "sleep 600ms")
+	long.Priority = 1
+	// next takes the slot the holder frees before the waiter can, so the
+	// waiter then waits for a worker with the lock free.
+	next := fakeCommand("next", "sleep 500ms", 0)
+	next.Priority = 4
+	config := newTestConfig(holder, waiter, long, next)
+	config.ThreadCount = 2
+
+	if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+		t.Fatalf("run failed: %v", errs)
+	}
+	if start := config.Results[1].Start.Sub(config.Results[0].Start); start < 500*time.Millisecond {
+		t.Fatalf("waiter started after %v, want it to wait for a worker too", start)
+	}
+	if waited := config.Results[1].Waited; waited < 50*time.Millisecond || waited > 400*time.Millisecond {
+		t.Errorf("waiter waited %v for the lock, want about 100ms", waited)
+	}
+
+	var output bytes.Buffer
+	printConcurrencySummary(&output, config)
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 || strings.Fields(lines[0])[0] != "GROUP" {
+		t.Fatalf("summary = %q, want a header and the db lock", lines)
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "db" || fields[1] != "1" || fields[2] != "2" {
+		t.Errorf("db row = %q, want max 1 and 2 commands", lines[1])
+	}
+}
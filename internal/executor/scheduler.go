@@ -1,39 +1,202 @@
 package executor
 
 import (
-	"sync"
+	"slices"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/unsubble/threadinator/internal/models"
 )
 
-func scheduleCommands(config *models.Config, executionOrder []int, poolChan chan *Worker, errorChan chan error, wg *sync.WaitGroup) {
-	history := make(map[int]*Worker)
-	for _, cmdIdx := range executionOrder {
-		wg.Add(1)
-		config.Logger.Debugf("Scheduling command with index %d: %s %v", cmdIdx, config.Commands[cmdIdx].Command, config.Commands[cmdIdx].Args)
-		w := <-poolChan
+type eventKind int
 
-		history[cmdIdx] = w
-		command := config.Commands[cmdIdx]
-		if command.Dependency != nil {
-			w.prev = history[*command.Dependency]
+const (
+	commandStarted eventKind = iota
+	commandFinished
+)
+
+type commandEvent struct {
+	kind  eventKind
+	index int
+}
+
+type scheduler struct {
+	config       *models.Config
+	run          *runState
+	poolChan     chan *Worker
+	errorChan    chan error
+	pending      []int
+	started      []bool
+	finished     []bool
+	admitted     []bool
+	granted      map[int]models.Resources
+	blockedSince map[int]time.Time
+}
+
+func scheduleCommands(config *models.Config, run *runState, executionOrder []int, poolChan chan *Worker, errorChan chan error) {
+	s := &scheduler{
+		config:       config,
+		run:          run,
+		poolChan:     poolChan,
+		errorChan:    errorChan,
+		pending:      prioritizeCommands(config, executionOrder),
+		started:      make([]bool, len(config.Commands)),
+		finished:     make([]bool, len(config.Commands)),
+		admitted:     make([]bool, len(config.Commands)),
+		granted:      make(map[int]models.Resources),
+		blockedSince: make(map[int]time.Time),
+	}
+
+	remaining := 0
+	for _, index := range executionOrder {
+		if config.Results[index].Restored {
+			s.started[index] = true
+			s.finished[index] = true
+		} else {
+			remaining++
+		}
+	}
+	s.pending = slices.DeleteFunc(s.pending, func(index int) bool {
+		return s.finished[index]
+	})
+
+	for remaining > 0 {
+		s.dispatchReady()
+		for _, index := range s.pending {
+			run.streams.starve(config.Commands[index], index)
+		}
+
+		event := <-run.events
+		switch event.kind {
+		case commandStarted:
+			s.started[event.index] = true
+			run.progress.started(event.index)
+		case commandFinished:
+			run.progress.finished(event.index, config.Results[event.index].Status)
+			s.started[event.index] = true
+			s.finished[event.index] = true
+			run.resources.release(s.granted[event.index])
+			delete(s.granted, event.index)
+			run.recorder.save(config)
+			remaining--
+		}
+	}
+
+	config.Logger.Debug("Execution completed, closing channels.")
+	close(errorChan)
+	close(poolChan)
+}
+
+func (s *scheduler) ready(index int) bool {
+	command := s.config.Commands[index]
+	for _, dependency := range command.Dependencies {
+		if s.finished[dependency] {
+			continue
+		}
+		if s.config.UsePipeline && command.If == "" && s.started[dependency] && len(s.config.Commands[dependency].Outputs) == 0 {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func (s *scheduler) dispatchReady() {
+	pending := s.pending[:0]
+	for position, index := range s.pending {
+		if len(s.poolChan) == 0 {
+			pending = append(pending, s.pending[position:]...)
+			break
+		}
+		if !s.ready(index) {
+			pending = append(pending, index)
+			continue
+		}
+		if !s.admitted[index] {
+			if err := evaluateCondition(s.config, index); err != nil {
+				s.started[index] = true
+				s.skipCommand(index, err)
+				continue
+			}
+			s.admitted[index] = true
+		}
+		if !s.acquire(index) {
+			pending = append(pending, index)
+			continue
 		}
 
-		go executeWorkerCommand(command, w, poolChan, errorChan)
+		command := s.config.Commands[index]
+		s.config.Logger.WithFields(logrus.Fields{
+			"index":   index,
+			"command": command.Command,
+			"args":    command.Args,
+		}).Debug("Scheduling command")
+		go executeWorkerCommand(index, command, <-s.poolChan, s.poolChan, s.run.events, s.errorChan)
 	}
+	s.pending = pending
+	s.pauseWaits()
 }
 
-func executeWorkerCommand(command *models.Command, w *Worker, poolChan chan *Worker, errorChan chan error) {
+// pauseWaits stops the wait clock of blocked commands whose resources are free
+// again, since waiting for a worker slot is not waiting for resources.
+func (s *scheduler) pauseWaits() {
+	for index, since := range s.blockedSince {
+		request := s.run.resources.grant(commandRequest(s.config.Commands[index]))
+		if s.run.resources.fits(request) {
+			s.config.Results[index].Waited += time.Since(since)
+			delete(s.blockedSince, index)
+		}
+	}
+}
+
+func (s *scheduler) acquire(index int) bool {
+	command := s.config.Commands[index]
+	request := commandRequest(command)
+	granted, ok := s.run.resources.tryAcquire(request)
+	if !ok {
+		if _, blocked := s.blockedSince[index]; blocked {
+			return false
+		}
+		s.blockedSince[index] = time.Now()
+		if s.config.Results[index].Waited == 0 {
+			s.config.Logger.WithFields(logrus.Fields{
+				"index":     index,
+				"command":   command.Command,
+				"resources": request,
+			}).Info("Waiting for resources")
+		}
+		return false
+	}
+
+	if since, blocked := s.blockedSince[index]; blocked {
+		s.config.Results[index].Waited += time.Since(since)
+		delete(s.blockedSince, index)
+	}
+	s.granted[index] = granted
+	return true
+}
+
+func executeWorkerCommand(index int, command *models.Command, w *Worker, poolChan chan *Worker, events chan<- commandEvent, errorChan chan error) {
+	w.index = index
 	w.command = command
+	w.attempt = 1
+
+	result := w.config.Results[index]
+	result.Start = time.Now()
 
 	defer func() {
-		recoverFromPanic(w, errorChan)
+		recoverFromPanic(w, result, errorChan)
 		poolChan <- w
+		events <- commandEvent{kind: commandFinished, index: index}
 	}()
 
-	w.config.Logger.Infof("[Thread-%d] Executing command: %s %v", w.id, w.command.Command, w.command.Args)
+	w.logger().WithField("args", w.command.Args).Info("Executing command")
 
-	if err := w.perform(); err != nil {
+	err := w.perform()
+	result.Finish(err)
+	if result.Status == models.RunStatusSkipped {
+		w.run.logSkip(w.logger(), err)
+	} else if err != nil {
 		errorChan <- err
 	}
 }
@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestSchedulerCountsEveryCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl)
+		statuses []string
+		failed   int
+	}{
+		{
+			name: "single worker with reversed dependencies",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				config := newTestConfig(fakeCommand("a", "echo a", 2), fakeCommand("b", "echo b", 0), fakeCommand("c", "echo c"))
+				config.ThreadCount = 1
+				return config, nil, nil
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSuccess, models.RunStatusSuccess},
+		},
+		{
+			name: "condition skips",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				skipped := fakeCommand("b", "echo b", 0)
+				skipped.If = "failure()"
+				after := fakeCommand("c", "echo c", 1)
+				after.If = "success()"
+				fallback := fakeCommand("d", "echo d", 1)
+				fallback.If = "skipped()"
+				config := newTestConfig(fakeCommand("a", "echo a"), skipped, after, fallback)
+				return config, nil, nil
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSkipped, models.RunStatusSkipped, models.RunStatusSuccess},
+		},
+		{
+			name: "condition skips a pipeline consumer",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				consumer := fakeCommand("b", "cat", 0)
+				consumer.If = "failure()"
+				config := newTestConfig(fakeCommand("a", "echo a"), consumer)
+				config.UsePipeline = true
+				return config, nil, nil
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSkipped},
+		},
+		{
+			name: "condition errors",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				broken := fakeCommand("b", "echo b", 0)
+				broken.If = "nope()"
+				config := newTestConfig(fakeCommand("a", "echo a"), broken, fakeCommand("c", "echo c", 1))
+				return config, nil, nil
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusFailed, models.RunStatusSuccess},
+			failed:   1,
+		},
+		{
+			name: "restored results",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				config := newTestConfig(fakeCommand("a", "echo a"), fakeCommand("b", "echo b", 0), fakeCommand("c", "echo c", 1))
+				resumed := &models.SavedRun{
+					RunID:    "previous",
+					Commands: config.Commands,
+					Results: []*models.SavedResult{
+						{Status: models.RunStatusSuccess, Outputs: map[string]string{"stdout": "a"}},
+						{Status: models.RunStatusFailed},
+						nil,
+					},
+				}
+				return config, resumed, nil
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSuccess, models.RunStatusSuccess},
+		},
+		{
+			name: "every result restored",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				config := newTestConfig(fakeCommand("a", "echo a"), fakeCommand("b", "echo b", 0))
+				resumed := &models.SavedRun{
+					RunID:    "previous",
+					Commands: config.Commands,
+					Results: []*models.SavedResult{
+						{Status: models.RunStatusSuccess},
+						{Status: models.RunStatusSuccess},
+					},
+				}
+				return config, resumed, nil
+			},
+			statuses: []string{models.RunStatusSuccess, models.RunStatusSuccess},
+		},
+		{
+			name: "cancellations",
+			setup: func(t *testing.T) (*models.Config, *models.SavedRun, *jobControl) {
+				config := newTestConfig(fakeCommand("a", "sleep 10s"), fakeCommand("b", "echo b", 0), fakeCommand("c", "echo c"))
+				jobs := newJobControl()
+				jobs.cancel(1)
+				time.AfterFunc(50*time.Millisecond, func() { jobs.cancel(0) })
+				return config, nil, jobs
+			},
+			statuses: []string{models.RunStatusSkipped, models.RunStatusSkipped, models.RunStatusSuccess},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, resumed, jobs := test.setup(t)
+			errs := runScheduler(t, config, resumed, jobs)
+			if len(errs) != test.failed {
+				t.Errorf("got %d errors %v, want %d", len(errs), errs, test.failed)
+			}
+			if got := statuses(config); strings.Join(got, ",") != strings.Join(test.statuses, ",") {
+				t.Errorf("statuses = %v, want %v", got, test.statuses)
+			}
+		})
+	}
+}
+
+func TestSchedulerCountsCacheSkips(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "input.txt"), []byte("one"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := func() []*models.Command {
+		build := fakeCommand("build", "echo build")
+		build.Inputs = []string{"*.txt"}
+		build.Cwd = dir
+		return []*models.Command{build, fakeCommand("after", "echo after", 0)}
+	}
+
+	first := newTestConfig(commands()...)
+	if errs := runScheduler(t, first, nil, nil); len(errs) != 0 {
+		t.Fatalf("first run failed: %v", errs)
+	}
+	if got := statuses(first); got[0] != models.RunStatusSuccess {
+		t.Fatalf("first run statuses = %v", got)
+	}
+
+	second := newTestConfig(commands()...)
+	if errs := runScheduler(t, second, nil, nil); len(errs) != 0 {
+		t.Fatalf("second run failed: %v", errs)
+	}
+	if got := statuses(second); got[0] != models.RunStatusSkipped || got[1] != models.RunStatusSuccess {
+		t.Fatalf("second run statuses = %v, want build skipped and after run", got)
+	}
+}
@@ -0,0 +1,239 @@
+package executor
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+var defaultIdentityFiles = []string{"id_ed25519", "id_ecdsa", "id_rsa"}
+
+var sshSignals = map[os.Signal]ssh.Signal{
+	os.Interrupt:    ssh.SIGINT,
+	os.Kill:         ssh.SIGKILL,
+	syscall.SIGTERM: ssh.SIGTERM,
+	syscall.SIGHUP:  ssh.SIGHUP,
+	syscall.SIGQUIT: ssh.SIGQUIT,
+}
+
+type sshExecutor struct {
+	processStreams
+	config  *models.Config
+	command *models.Command
+	env     *environment
+	logger  *logrus.Entry
+	client  *ssh.Client
+	session *ssh.Session
+}
+
+func newSSHExecutor(config *models.Config, command *models.Command, env *environment, logger *logrus.Entry) *sshExecutor {
+	return &sshExecutor{
+		processStreams: newProcessStreams(),
+		config:         config,
+		command:        command,
+		env:            env,
+		logger:         logger,
+	}
+}
+
+func (e *sshExecutor) Start(ctx context.Context) error {
+	host := e.command.Host
+	client, err := dialSSH(ctx, e.config, host)
+	if err != nil {
+		return err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return models.NewSSHError(host.String(), err)
+	}
+
+	if e.stdin != nil {
+		stdin, err := session.StdinPipe()
+		if err != nil {
+			client.Close()
+			return models.NewSSHError(host.String(), err)
+		}
+		go feedStdin(stdin, e.stdin)
+	}
+	session.Stdout = e.stdoutWriter
+	session.Stderr = e.stderrWriter
+
+	commandLine := remoteCommandLine(e.command, e.env)
+	e.logger.WithField("host", host.String()).Debugf("Running remote command: %s", commandLine)
+	if err := session.Start(commandLine); err != nil {
+		client.Close()
+		return models.NewCommandError(e.command.Command, err.Error())
+	}
+
+	e.client = client
+	e.session = session
+
+	go func() {
+		err := session.Wait()
+		client.Close()
+		e.finish(err)
+	}()
+	return nil
+}
+
+func (e *sshExecutor) Signal(sig os.Signal) error {
+	if e.session == nil {
+		return nil
+	}
+
+	err := e.session.Signal(sshSignals[sig])
+	if sig == os.Kill {
+		return e.client.Close()
+	}
+	return err
+}
+
+func dialSSH(ctx context.Context, config *models.Config, host *models.Host) (*ssh.Client, error) {
+	hostKeyCallback, err := knownhosts.New(knownHostsPath(config))
+	if err != nil {
+		return nil, models.NewSSHError(host.String(), err)
+	}
+
+	auth, closeAgent := sshAuthMethods(config)
+	defer closeAgent()
+
+	clientConfig := &ssh.ClientConfig{
+		User:            host.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         config.Timeout,
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host.Address)
+	if err != nil {
+		return nil, models.NewSSHError(host.String(), err)
+	}
+
+	// ClientConfig.Timeout only bounds the dial, so the handshake gets its own
+	// deadline and is abandoned when ctx is cancelled.
+	if config.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(config.Timeout))
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Unix(1, 0)) })
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, host.Address, clientConfig)
+	stop()
+	if err != nil {
+		conn.Close()
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, models.NewSSHError(host.String(), err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return ssh.NewClient(clientConn, channels, requests), nil
+}
+
+func sshAuthMethods(config *models.Config) ([]ssh.AuthMethod, func()) {
+	var methods []ssh.AuthMethod
+	closeAgent := func() {}
+
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+			closeAgent = func() { conn.Close() }
+		} else {
+			config.Logger.WithError(err).Debug("SSH agent unavailable")
+		}
+	}
+
+	var signers []ssh.Signer
+	for _, path := range identityFiles(config) {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			config.Logger.WithError(err).WithField("identity", path).Debug("Skipping SSH identity")
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+
+	return methods, closeAgent
+}
+
+func identityFiles(config *models.Config) []string {
+	if config.SSHIdentity != "" {
+		return []string{config.SSHIdentity}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	for _, name := range defaultIdentityFiles {
+		paths = append(paths, filepath.Join(home, ".ssh", name))
+	}
+	return paths
+}
+
+func knownHostsPath(config *models.Config) string {
+	if config.KnownHosts != "" {
+		return config.KnownHosts
+	}
+
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".ssh", "known_hosts")
+}
+
+// remoteCommandLine only changes into a per-command cwd: the global cwd is a
+// local directory and says nothing about the layout of the remote host.
+func remoteCommandLine(command *models.Command, env *environment) string {
+	var parts []string
+	if command.Cwd != "" {
+		parts = append(parts, "cd", shellQuote(command.Cwd), "&&")
+	}
+
+	if env.clear || len(env.set) > 0 || len(env.unset) > 0 {
+		parts = append(parts, "env")
+		if env.clear {
+			parts = append(parts, "-i")
+		}
+		for _, name := range env.unset {
+			parts = append(parts, "-u", shellQuote(name))
+		}
+		for _, name := range env.names() {
+			parts = append(parts, shellQuote(name+"="+env.set[name]))
+		}
+	}
+
+	parts = append(parts, shellQuote(command.Command))
+	for _, arg := range command.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,@%+", r))
+	}) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
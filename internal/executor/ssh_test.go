@@ -0,0 +1,192 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// startSSHServer serves exec requests by echoing the command line back, and
+// returns its address with a config that trusts it and holds the client key.
+func startSSHServer(t *testing.T, handshake bool) (string, *models.Config) {
+	t.Helper()
+	t.Setenv("SSH_AUTH_SOCK", "")
+	dir := t.TempDir()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPublic, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorized, err := ssh.NewPublicKey(clientPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(identity, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	address := listener.Addr().String()
+
+	knownHosts := filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(address)}, hostSigner.PublicKey()) + "\n"
+	if err := os.WriteFile(knownHosts, []byte(line), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	serverConfig := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	serverConfig.AddHostKey(hostSigner)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			if !handshake {
+				go io.Copy(io.Discard, conn)
+				continue
+			}
+			go serveSSH(conn, serverConfig)
+		}
+	}()
+
+	config := newTestConfig()
+	config.SSHIdentity = identity
+	config.KnownHosts = knownHosts
+	return address, config
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	server, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	defer server.Close()
+	go ssh.DiscardRequests(requests)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "sessions only")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer channel.Close()
+			for request := range requests {
+				if request.Type != "exec" {
+					request.Reply(false, nil)
+					continue
+				}
+				var exec struct{ Command string }
+				ssh.Unmarshal(request.Payload, &exec)
+				request.Reply(true, nil)
+				io.WriteString(channel, exec.Command+"\n")
+				channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+				return
+			}
+		}()
+	}
+}
+
+func TestSSHExecutorRunsRemoteCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		globalCwd string
+		cwd       string
+		want      string
+	}{
+		{name: "login directory", want: "echo hello world"},
+		{name: "global cwd stays local", globalCwd: "/local/checkout", want: "echo hello world"},
+		{name: "per-command cwd", globalCwd: "/local/checkout", cwd: "/srv/app", want: "cd /srv/app && echo hello world"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address, config := startSSHServer(t, true)
+			remote := fakeCommand("remote", "echo hello world")
+			remote.Backend = models.BackendSSH
+			remote.Host = &models.Host{User: "deploy", Address: address}
+			remote.Outputs = []string{"stdout"}
+			remote.Cwd = test.cwd
+			config.Commands = []*models.Command{remote}
+			config.ThreadCount = 1
+			config.Cwd = test.globalCwd
+
+			if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+				t.Fatalf("remote command failed: %v", errs)
+			}
+			if got := config.Results[0].Outputs["stdout"]; got != test.want {
+				t.Fatalf("remote command line = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSSHHandshakeTimeout(t *testing.T) {
+	address, config := startSSHServer(t, false)
+	config.Timeout = 100 * time.Millisecond
+	host := &models.Host{User: "deploy", Address: address}
+
+	started := time.Now()
+	_, err := dialSSH(context.Background(), config, host)
+	var sshErr *models.SSHError
+	if !errors.As(err, &sshErr) {
+		t.Fatalf("dialSSH() = %v, want an SSH error", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("handshake with a silent server took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	config.Timeout = 0
+	time.AfterFunc(100*time.Millisecond, cancel)
+	started = time.Now()
+	if _, err := dialSSH(ctx, config, host); !errors.As(err, &sshErr) || sshErr.Cause != context.Canceled {
+		t.Fatalf("dialSSH() after cancel = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("cancelling the handshake took %v", elapsed)
+	}
+}
@@ -0,0 +1,273 @@
+package executor
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/history"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const keptRuns = 50
+
+type runRecorder struct {
+	store *history.RunStore
+	state *models.SavedRun
+}
+
+func newRunRecorder(config *models.Config, resumed *models.SavedRun) *runRecorder {
+	if !config.RecordHistory && config.ReportFile == "" || len(config.Commands) == 0 {
+		return nil
+	}
+
+	var store *history.RunStore
+	if config.RecordHistory {
+		store = history.NewRunStore(historyPath(config))
+		if err := store.Prune(keptRuns - 1); err != nil {
+			config.Logger.WithError(err).Warn("Failed to prune saved runs")
+		}
+	}
+
+	state := &models.SavedRun{
+		RunID:       config.RunID,
+		Created:     time.Now(),
+		Pipeline:    config.UsePipeline,
+		ThreadCount: config.ThreadCount,
+		Vars:        config.Vars,
+		Commands:    config.Commands,
+		Results:     make([]*models.SavedResult, len(config.Commands)),
+	}
+	r := &runRecorder{store: store, state: state}
+	if resumed != nil {
+		state.ResumedFrom = resumed.RunID
+		for index, result := range config.Results {
+			if result.Restored {
+				state.Results[index] = r.keepStdout(config, index, resumed.Results[index])
+			}
+		}
+	}
+	return r
+}
+
+// keepStdout copies a restored result's stdout into this run's directory,
+// since pruning may remove the run it was restored from.
+func (r *runRecorder) keepStdout(config *models.Config, index int, saved *models.SavedResult) *models.SavedResult {
+	if !r.keepsState() || saved.Stdout == "" || saved.Stdout == r.stdoutPath(index) {
+		return saved
+	}
+
+	source, err := os.Open(saved.Stdout)
+	if err != nil {
+		config.Logger.WithError(err).WithField("command", index).Warn("Failed to keep restored stdout")
+		return saved
+	}
+	defer source.Close()
+
+	target, err := r.openStdout(index, 1)
+	if err == nil {
+		_, err = io.Copy(target, source)
+		if closeErr := target.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		config.Logger.WithError(err).WithField("command", index).Warn("Failed to keep restored stdout")
+		return saved
+	}
+
+	kept := *saved
+	kept.Stdout = r.stdoutPath(index)
+	return &kept
+}
+
+func (r *runRecorder) keepsState() bool {
+	return r != nil && r.store != nil
+}
+
+func (r *runRecorder) stdoutPath(index int) string {
+	return filepath.Join(r.store.Dir(r.state.RunID), strconv.Itoa(index)+".stdout")
+}
+
+func (r *runRecorder) openStdout(index int, attempt int) (io.WriteCloser, error) {
+	path := r.stdoutPath(index)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, models.NewFileOpenError(path, err)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if attempt <= 1 {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		return nil, models.NewFileOpenError(path, err)
+	}
+	return file, nil
+}
+
+func (r *runRecorder) save(config *models.Config) {
+	if r == nil {
+		return
+	}
+
+	for index, result := range config.Results {
+		if result.Restored || !result.Finished() {
+			continue
+		}
+
+		saved := newSavedResult(result)
+		if r.keepsState() && fileExists(r.stdoutPath(index)) {
+			saved.Stdout = r.stdoutPath(index)
+		}
+		r.state.Results[index] = saved
+	}
+
+	r.state.Updated = time.Now()
+	if !r.keepsState() {
+		return
+	}
+	if err := r.store.Save(r.state); err != nil {
+		config.Logger.WithError(err).Warn("Failed to save run state")
+	}
+}
+
+func newSavedResult(result *models.Result) *models.SavedResult {
+	saved := &models.SavedResult{
+		Status:  result.Status,
+		Start:   result.Start,
+		End:     result.End,
+		Outputs: result.Outputs,
+	}
+	if result.Err != nil {
+		saved.Error = result.Err.Error()
+	}
+	return saved
+}
+
+func (r *runRecorder) writeReport(config *models.Config) {
+	if r == nil || config.ReportFile == "" {
+		return
+	}
+	if err := history.WriteRun(config.ReportFile, r.state); err != nil {
+		config.Logger.WithError(err).Warn("Failed to write run report")
+	}
+}
+
+func failedCommands(saved *models.SavedRun, logger *logrus.Logger) *models.SavedRun {
+	failed := func(index int) bool {
+		if index >= len(saved.Results) || saved.Results[index] == nil {
+			return false
+		}
+		status := saved.Results[index].Status
+		return status == models.RunStatusFailed || status == models.RunStatusTimeout
+	}
+	succeeded := func(index int) bool {
+		return index < len(saved.Results) && saved.Results[index] != nil && saved.Results[index].Status == models.RunStatusSuccess
+	}
+
+	keep := make([]bool, len(saved.Commands))
+	for index, command := range saved.Commands {
+		if !failed(index) {
+			continue
+		}
+		keep[index] = true
+		for _, dependency := range command.Dependencies {
+			if succeeded(dependency) {
+				keep[dependency] = true
+			}
+		}
+	}
+
+	positions := make(map[int]int)
+	rerun := &models.SavedRun{
+		RunID:       saved.RunID,
+		Pipeline:    saved.Pipeline,
+		ThreadCount: saved.ThreadCount,
+		Vars:        saved.Vars,
+	}
+	for index, command := range saved.Commands {
+		if !keep[index] {
+			continue
+		}
+		positions[index] = len(rerun.Commands)
+		clone := *command
+		rerun.Commands = append(rerun.Commands, &clone)
+		if failed(index) {
+			rerun.Results = append(rerun.Results, nil)
+		} else {
+			rerun.Results = append(rerun.Results, saved.Results[index])
+		}
+	}
+
+	for _, command := range rerun.Commands {
+		var dependencies []int
+		for _, dependency := range command.Dependencies {
+			if position, kept := positions[dependency]; kept {
+				dependencies = append(dependencies, position)
+				continue
+			}
+			logger.WithFields(logrus.Fields{
+				"command":    command.Key(),
+				"dependency": saved.Commands[dependency].Key(),
+			}).Warn("Dropping dependency on a command that neither succeeded nor failed")
+		}
+		command.Dependencies = dependencies
+	}
+	return rerun
+}
+
+func restoreResults(config *models.Config, resumed *models.SavedRun) {
+	if resumed == nil {
+		return
+	}
+	for index, result := range config.Results {
+		if index >= len(resumed.Results) {
+			break
+		}
+		if saved := resumed.Results[index]; saved != nil && saved.Status == models.RunStatusSuccess {
+			result.Restore(saved)
+		}
+	}
+}
+
+func (w *Worker) parentOutputs() ([]io.Reader, func(), error) {
+	var readers []io.Reader
+	var files []*os.File
+	closeFiles := func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}
+	if !w.config.UsePipeline {
+		return nil, closeFiles, nil
+	}
+
+	for _, dependency := range w.command.Dependencies {
+		if path, restored := w.run.restored[dependency]; restored {
+			if path == "" {
+				continue
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				closeFiles()
+				return nil, nil, models.NewFileOpenError(path, err)
+			}
+			files = append(files, file)
+			readers = append(readers, file)
+			continue
+		}
+		if stream := w.run.streams[dependency]; stream != nil {
+			readers = append(readers, stream.subscribers[w.index])
+		}
+	}
+	return readers, closeFiles, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
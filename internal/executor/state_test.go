@@ -0,0 +1,147 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/history"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestResumeKeepsRestoredStdout(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	store := history.NewRunStore(historyPath)
+
+	previous := filepath.Join(store.Dir("previous"), "0.stdout")
+	if err := os.MkdirAll(filepath.Dir(previous), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(previous, []byte("produced\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := []*models.Command{
+		fakeCommand("producer", "echo producer"),
+		fakeCommand("consumer", "echo ${name}", 0),
+		fakeCommand("last", "echo last", 1),
+	}
+	saved := &models.SavedRun{
+		RunID:       "previous",
+		Pipeline:    true,
+		ThreadCount: 2,
+		Vars:        map[string]string{"name": "saved"},
+		Commands:    commands,
+		Results: []*models.SavedResult{
+			{Status: models.RunStatusSuccess, Stdout: previous},
+			{Status: models.RunStatusFailed},
+			nil,
+		},
+	}
+
+	config := newTestConfig()
+	config.RecordHistory = true
+	config.HistoryFile = historyPath
+	config.Vars = map[string]string{"name": "override"}
+	if err := Resume(context.Background(), config, saved); err != nil {
+		t.Fatalf("Resume() = %v", err)
+	}
+	if err := os.RemoveAll(store.Dir("previous")); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := store.Load(config.RunID)
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	kept := resumed.Results[0].Stdout
+	if filepath.Dir(kept) != store.Dir(config.RunID) {
+		t.Fatalf("restored stdout kept at %s, want inside %s", kept, store.Dir(config.RunID))
+	}
+	if data, err := os.ReadFile(kept); err != nil || string(data) != "produced\n" {
+		t.Fatalf("restored stdout = %q, %v", data, err)
+	}
+
+	consumer, err := os.ReadFile(resumed.Results[1].Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "echo override\nproduced\n"; string(consumer) != want {
+		t.Fatalf("consumer stdout = %q, want %q", consumer, want)
+	}
+
+	info, err := os.Stat(filepath.Join(store.Dir(config.RunID), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Fatalf("state.json mode = %v, want 0600", mode)
+	}
+}
+
+func TestEmptyRunsAreNotRecorded(t *testing.T) {
+	dir := t.TempDir()
+	config := newTestConfig()
+	config.ThreadCount = 1
+	config.RecordHistory = true
+	config.HistoryFile = filepath.Join(dir, "history.jsonl")
+	config.ReportFile = filepath.Join(dir, "report.json")
+
+	if err := Execute(context.Background(), config); err != nil {
+		t.Fatalf("Execute() = %v", err)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Fatalf("an empty run left %v, %v behind", entries, err)
+	}
+}
+
+func TestRerunFailedRestoresSuccessfulParents(t *testing.T) {
+	test := fakeCommand("test", "echo ${jobs.build.outputs.version}", 1, 2)
+	test.Outputs = []string{"stdout"}
+	report := &models.SavedRun{
+		RunID:       "report",
+		ThreadCount: 2,
+		Commands: []*models.Command{
+			fakeCommand("lint", "echo lint"),
+			fakeCommand("build", "echo build"),
+			fakeCommand("docs", "echo docs"),
+			test,
+			fakeCommand("deploy", "echo deploy", 3),
+		},
+		Results: []*models.SavedResult{
+			{Status: models.RunStatusSuccess},
+			{Status: models.RunStatusSuccess, Outputs: map[string]string{"version": "1.2.0"}},
+			{Status: models.RunStatusSkipped},
+			{Status: models.RunStatusFailed},
+			nil,
+		},
+	}
+
+	config := newTestConfig()
+	recorder := &warningRecorder{}
+	config.Logger.AddHook(recorder)
+	if err := RerunFailed(context.Background(), config, report); err != nil {
+		t.Fatalf("RerunFailed() = %v", err)
+	}
+
+	if len(config.Commands) != 2 || config.Commands[0].Name != "build" || config.Commands[1].Name != "test" {
+		t.Fatalf("reran %v, want build and test", config.Commands)
+	}
+	if deps := config.Commands[1].Dependencies; len(deps) != 1 || deps[0] != 0 {
+		t.Errorf("test depends on %v, want the restored build at 0", deps)
+	}
+	if build := config.Results[0]; !build.Restored || build.Status != models.RunStatusSuccess {
+		t.Errorf("build = %+v, want it restored", build)
+	}
+	if got := config.Results[1].Outputs["stdout"]; got != "echo 1.2.0" {
+		t.Errorf("test stdout = %q, want the restored build output", got)
+	}
+	if want := []string{"test -> docs"}; !slices.Equal(recorder.warnings, want) {
+		t.Errorf("warnings %q, want %q", recorder.warnings, want)
+	}
+	if report.Commands[3].Dependencies[0] != 1 {
+		t.Error("the report's commands were modified")
+	}
+}
@@ -0,0 +1,280 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const defaultTeeBuffer = 64 * 1024
+
+type outputStreams map[int]*broadcaster
+
+func newOutputStreams(config *models.Config) outputStreams {
+	streams := make(outputStreams)
+	if !config.UsePipeline {
+		return streams
+	}
+
+	limit := config.TeeBuffer
+	if limit <= 0 {
+		limit = defaultTeeBuffer
+	}
+
+	for index, command := range config.Commands {
+		if config.Results[index].Restored {
+			continue
+		}
+
+		var consumers []int
+		for consumer, other := range config.Commands {
+			if slices.Contains(other.Dependencies, index) {
+				consumers = append(consumers, consumer)
+			}
+		}
+		if len(consumers) == 0 {
+			continue
+		}
+
+		policy := command.Tee
+		if policy == "" {
+			policy = config.TeePolicy
+		}
+		if policy == "" {
+			policy = models.TeeBlock
+		}
+
+		logger := config.Logger.WithFields(logrus.Fields{"index": index, "command": command.Command})
+		streams[index] = newBroadcaster(policy, limit, consumers, logger)
+	}
+	return streams
+}
+
+func (s outputStreams) finish(command *models.Command, index int) {
+	if stream := s[index]; stream != nil {
+		stream.Close()
+	}
+	for _, dependency := range command.Dependencies {
+		if stream := s[dependency]; stream != nil {
+			stream.subscribers[index].detach()
+		}
+	}
+}
+
+// starve lets the producers feeding a command that cannot start yet spill its
+// output rather than wait for it, so a blocked producer cannot hold the worker
+// or resources the command is waiting for.
+func (s outputStreams) starve(command *models.Command, index int) {
+	for _, dependency := range command.Dependencies {
+		if stream := s[dependency]; stream != nil {
+			stream.subscribers[index].starve()
+		}
+	}
+}
+
+func (s outputStreams) release() {
+	for _, stream := range s {
+		for _, subscriber := range stream.subscribers {
+			subscriber.detach()
+		}
+	}
+}
+
+type broadcaster struct {
+	subscribers map[int]*subscription
+	logger      *logrus.Entry
+}
+
+func newBroadcaster(policy string, limit int, consumers []int, logger *logrus.Entry) *broadcaster {
+	b := &broadcaster{
+		subscribers: make(map[int]*subscription),
+		logger:      logger,
+	}
+	for _, consumer := range consumers {
+		s := &subscription{policy: policy, limit: limit}
+		s.cond = sync.NewCond(&s.mu)
+		b.subscribers[consumer] = s
+	}
+	return b
+}
+
+func (b *broadcaster) Write(p []byte) (int, error) {
+	for _, subscriber := range b.subscribers {
+		subscriber.write(p)
+	}
+	return len(p), nil
+}
+
+func (b *broadcaster) Close() error {
+	for consumer, subscriber := range b.subscribers {
+		if dropped := subscriber.close(); dropped > 0 {
+			b.logger.WithFields(logrus.Fields{"consumer": consumer, "bytes": dropped}).Warn("Dropped output for slow consumer")
+		}
+	}
+	return nil
+}
+
+func (b *broadcaster) unblock() {
+	for _, subscriber := range b.subscribers {
+		subscriber.unblock()
+	}
+}
+
+type subscription struct {
+	mu           sync.Mutex
+	cond         *sync.Cond
+	policy       string
+	limit        int
+	chunks       [][]byte
+	size         int
+	spill        *os.File
+	spillRead    int64
+	spillWritten int64
+	dropped      int64
+	attached     bool
+	starved      bool
+	unblocked    bool
+	closed       bool
+	detached     bool
+	err          error
+}
+
+func (s *subscription) write(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.policy == models.TeeBlock {
+		for !s.early() && !s.unblocked && !s.detached && !s.spilling() && s.size > 0 && s.size+len(p) > s.limit {
+			s.cond.Wait()
+		}
+	}
+
+	overflow := s.size+len(p) > s.limit
+	switch {
+	case s.detached:
+		return
+	case s.spilling() || overflow && (s.early() || s.unblocked || s.policy == models.TeeSpill):
+		s.writeSpill(p)
+	case overflow && s.policy == models.TeeDrop:
+		s.dropped += int64(len(p))
+	default:
+		s.chunks = append(s.chunks, bytes.Clone(p))
+		s.size += len(p)
+	}
+	s.cond.Broadcast()
+}
+
+func (s *subscription) writeSpill(p []byte) {
+	if s.err != nil {
+		return
+	}
+	if s.spill == nil {
+		file, err := os.CreateTemp("", "threadinator-spill-*")
+		if err != nil {
+			s.err = models.NewFileOpenError(os.TempDir(), err)
+			return
+		}
+		s.spill = file
+	}
+
+	n, err := s.spill.WriteAt(p, s.spillWritten)
+	s.spillWritten += int64(n)
+	if err != nil {
+		s.err = models.NewFileOpenError(s.spill.Name(), err)
+	}
+}
+
+// early reports output the consumer cannot read yet. It is spilled, except
+// under block, where the producer waits until the consumer starts or starves.
+func (s *subscription) early() bool {
+	return !s.attached && (s.policy != models.TeeBlock || s.starved)
+}
+
+func (s *subscription) spilling() bool {
+	return s.spillRead < s.spillWritten
+}
+
+func (s *subscription) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attached = true
+	for len(s.chunks) == 0 && !s.spilling() && !s.closed && !s.detached && s.err == nil {
+		s.cond.Wait()
+	}
+	defer s.cond.Broadcast()
+
+	if len(s.chunks) > 0 {
+		n := copy(p, s.chunks[0])
+		s.chunks[0] = s.chunks[0][n:]
+		if len(s.chunks[0]) == 0 {
+			s.chunks = s.chunks[1:]
+		}
+		s.size -= n
+		return n, nil
+	}
+
+	if s.spilling() {
+		n, err := s.spill.ReadAt(p[:min(int64(len(p)), s.spillWritten-s.spillRead)], s.spillRead)
+		s.spillRead += int64(n)
+		if !s.spilling() {
+			s.spill.Truncate(0)
+			s.spillRead, s.spillWritten = 0, 0
+		}
+		if err != nil && err != io.EOF {
+			return n, models.NewOutputReadError(err)
+		}
+		return n, nil
+	}
+
+	if s.err != nil {
+		return 0, s.err
+	}
+	return 0, io.EOF
+}
+
+func (s *subscription) starve() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.starved = true
+	s.cond.Broadcast()
+}
+
+func (s *subscription) unblock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unblocked = true
+	s.cond.Broadcast()
+}
+
+func (s *subscription) close() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+	return s.dropped
+}
+
+func (s *subscription) detach() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.detached {
+		return
+	}
+
+	s.detached = true
+	s.chunks = nil
+	s.size = 0
+	if s.spill != nil {
+		s.spill.Close()
+		os.Remove(s.spill.Name())
+		s.spill = nil
+		s.spillRead, s.spillWritten = 0, 0
+	}
+	s.cond.Broadcast()
+}
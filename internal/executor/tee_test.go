@@ -0,0 +1,233 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const blockedFor = 50 * time.Millisecond
+
+func newTestBroadcaster(policy string, limit int) (*broadcaster, *subscription) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	b := newBroadcaster(policy, limit, []int{1}, logrus.NewEntry(logger))
+	return b, b.subscribers[1]
+}
+
+// writeAsync writes p in the background and reports whether it returned
+// within blockedFor.
+func writeAsync(b *broadcaster, p []byte) (chan struct{}, bool) {
+	done := make(chan struct{})
+	go func() {
+		b.Write(p)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return done, true
+	case <-time.After(blockedFor):
+		return done, false
+	}
+}
+
+func waitDone(t *testing.T, done chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(schedulerDeadline):
+		t.Fatalf("%s never returned", what)
+	}
+}
+
+func attach(s *subscription) {
+	s.mu.Lock()
+	s.attached = true
+	s.mu.Unlock()
+}
+
+func TestTeePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		attached bool
+		blocks   bool
+		spills   bool
+		dropped  bool
+	}{
+		{name: "block waits for an attached consumer", policy: models.TeeBlock, attached: true, blocks: true},
+		{name: "block waits for a consumer that can still start", policy: models.TeeBlock, blocks: true},
+		{name: "spill", policy: models.TeeSpill, attached: true, spills: true},
+		{name: "spill before the consumer starts", policy: models.TeeSpill, spills: true},
+		{name: "drop", policy: models.TeeDrop, attached: true, dropped: true},
+		{name: "drop before the consumer starts spills", policy: models.TeeDrop, spills: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, s := newTestBroadcaster(test.policy, 4)
+			if test.attached {
+				attach(s)
+			}
+
+			b.Write([]byte("abcd"))
+			done, returned := writeAsync(b, []byte("efgh"))
+			if returned == test.blocks {
+				t.Fatalf("second write returned = %v, want blocked = %v", returned, test.blocks)
+			}
+			if test.blocks {
+				buffer := make([]byte, 4)
+				if n, _ := s.Read(buffer); string(buffer[:n]) != "abcd" {
+					t.Fatalf("read %q, want abcd", buffer[:n])
+				}
+				waitDone(t, done, "blocked write")
+			}
+			if s.size > s.limit {
+				t.Errorf("buffered %d bytes, limit %d", s.size, s.limit)
+			}
+			if spilled := s.spill != nil; spilled != test.spills {
+				t.Errorf("spilled = %v, want %v", spilled, test.spills)
+			}
+
+			b.Close()
+			if dropped := s.close(); (dropped > 0) != test.dropped {
+				t.Errorf("dropped %d bytes, want dropped = %v", dropped, test.dropped)
+			}
+			rest, err := io.ReadAll(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "abcdefgh"
+			switch {
+			case test.blocks:
+				want = "efgh"
+			case test.dropped:
+				want = "abcd"
+			}
+			if string(rest) != want {
+				t.Errorf("consumer read %q, want %q", rest, want)
+			}
+			s.detach()
+		})
+	}
+}
+
+func TestTeeSlowConsumer(t *testing.T) {
+	b, s := newTestBroadcaster(models.TeeBlock, 64)
+	input := bytes.Repeat([]byte("0123456789abcdef"), 256)
+
+	read := make(chan []byte)
+	go func() {
+		var output []byte
+		buffer := make([]byte, 7)
+		for {
+			n, err := s.Read(buffer)
+			output = append(output, buffer[:n]...)
+			if err != nil {
+				read <- output
+				return
+			}
+			time.Sleep(time.Microsecond)
+		}
+	}()
+
+	for start := 0; start < len(input); start += 48 {
+		b.Write(input[start:min(start+48, len(input))])
+		s.mu.Lock()
+		size := s.size
+		s.mu.Unlock()
+		if size > s.limit {
+			t.Fatalf("buffered %d bytes, limit %d", size, s.limit)
+		}
+	}
+	b.Close()
+
+	if output := <-read; !bytes.Equal(output, input) {
+		t.Fatalf("consumer read %d bytes, want %d in order", len(output), len(input))
+	}
+	if s.spill != nil {
+		t.Error("block spilled for an attached consumer")
+	}
+}
+
+func TestTeeReleasesBlockedProducers(t *testing.T) {
+	tests := []struct {
+		name    string
+		attach  bool
+		release func(b *broadcaster, s *subscription)
+		want    string
+	}{
+		{name: "cancelled producer", attach: true, release: func(b *broadcaster, s *subscription) { b.unblock() }, want: "abcdefgh"},
+		{name: "starved consumer", release: func(b *broadcaster, s *subscription) { s.starve() }, want: "abcdefgh"},
+		{name: "finished consumer", attach: true, release: func(b *broadcaster, s *subscription) { s.detach() }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, s := newTestBroadcaster(models.TeeBlock, 4)
+			if test.attach {
+				attach(s)
+			}
+			b.Write([]byte("abcd"))
+			done, returned := writeAsync(b, []byte("efgh"))
+			if returned {
+				t.Fatal("write did not block on a full buffer")
+			}
+
+			test.release(b, s)
+			waitDone(t, done, "blocked write")
+			b.Close()
+			if test.want == "" {
+				return
+			}
+			if output, err := io.ReadAll(s); err != nil || string(output) != test.want {
+				t.Errorf("consumer read %q, %v; want %q", output, err, test.want)
+			}
+			s.detach()
+		})
+	}
+}
+
+func TestTeeCloseWakesBlockedReader(t *testing.T) {
+	b, s := newTestBroadcaster(models.TeeBlock, 4)
+
+	read := make(chan error)
+	go func() {
+		_, err := s.Read(make([]byte, 4))
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		t.Fatalf("Read returned %v before any output", err)
+	case <-time.After(blockedFor):
+	}
+
+	b.Close()
+	select {
+	case err := <-read:
+		if err != io.EOF {
+			t.Fatalf("Read after Close = %v, want EOF", err)
+		}
+	case <-time.After(schedulerDeadline):
+		t.Fatal("Close did not wake the blocked reader")
+	}
+}
+
+func TestSchedulerStarvesPendingConsumers(t *testing.T) {
+	producer := fakeCommand("producer", "echo "+string(bytes.Repeat([]byte("x"), 1024)))
+	config := newTestConfig(producer, fakeCommand("consumer", "cat", 0))
+	config.UsePipeline = true
+	config.TeeBuffer = 16
+	config.ThreadCount = 1
+
+	if errs := runScheduler(t, config, nil, nil); len(errs) != 0 {
+		t.Fatalf("pipeline with one worker failed: %v", errs)
+	}
+	if got := statuses(config); got[0] != models.RunStatusSuccess || got[1] != models.RunStatusSuccess {
+		t.Fatalf("statuses = %v", got)
+	}
+}
@@ -1,29 +1,35 @@
 package executor
 
 import (
-	"sync"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
 
 	"github.com/unsubble/threadinator/internal/models"
 )
 
-func initializeWorkers(threadCount int, poolChan chan *Worker, wg *sync.WaitGroup, config *models.Config) {
-	config.Logger.Infof("Initializing %d workers", threadCount)
+func initializeWorkers(threadCount int, poolChan chan *Worker, config *models.Config, run *runState) {
+	config.Logger.WithField("workers", threadCount).Info("Initializing workers")
 	for i := range threadCount {
-		worker := newWorker(i, wg, config)
+		worker := newWorker(i, config, run)
 		poolChan <- worker
 	}
 }
 
-func finalizeExecution(wg *sync.WaitGroup, errorChan chan error, poolChan chan *Worker, config *models.Config) {
-	wg.Wait()
-	config.Logger.Debug("Execution completed, closing channels.")
-	close(errorChan)
-	close(poolChan)
-}
-
 func collectErrors(config *models.Config, errorChan <-chan error) error {
+	failed := 0
 	for err := range errorChan {
 		config.Logger.Errorf("%v", err)
+		failed++
+	}
+	if failed > 0 {
+		return models.NewExecutionError(failed, len(config.Commands))
 	}
 	return nil
 }
+
+func newRunID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,392 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/history"
+	"github.com/unsubble/threadinator/internal/models"
+	"github.com/unsubble/threadinator/internal/parsers"
+)
+
+const defaultPollInterval = time.Second
+
+type watcher struct {
+	config       *models.Config
+	status       *logrus.Logger
+	commands     []*models.Command
+	dependents   [][]int
+	fingerprints []string
+	results      []*models.SavedResult
+	runID        string
+	round        *watchRound
+	pending      []bool
+	done         chan error
+}
+
+type watchRound struct {
+	config  *models.Config
+	jobs    *jobControl
+	indexes []int
+}
+
+func Watch(ctx context.Context, config *models.Config, debounce, poll time.Duration) error {
+	if _, err := resolveExecutionOrder(config); err != nil {
+		config.Logger.Errorf("Execution order resolution failed: %v", err)
+		return err
+	}
+	dependents, err := dependentsOf(config.Commands)
+	if err != nil {
+		return err
+	}
+
+	w := &watcher{
+		config:     config,
+		status:     statusLogger(config),
+		commands:   config.Commands,
+		dependents: dependents,
+		results:    make([]*models.SavedResult, len(config.Commands)),
+		pending:    make([]bool, len(config.Commands)),
+		done:       make(chan error, 1),
+	}
+	w.fingerprints = w.fingerprint()
+
+	var notifier *fsnotify.Watcher
+	if poll <= 0 {
+		notifier, err = fsnotify.NewWatcher()
+		if err == nil {
+			err = w.addDirectories(notifier, w.watchRoots())
+		}
+		if err != nil {
+			config.Logger.WithError(err).Warn("File notifications unavailable, polling for changes")
+			if notifier != nil {
+				notifier.Close()
+				notifier = nil
+			}
+			poll = defaultPollInterval
+		}
+	}
+
+	var events <-chan fsnotify.Event
+	var notifyErrors <-chan error
+	if notifier != nil {
+		defer notifier.Close()
+		events, notifyErrors = notifier.Events, notifier.Errors
+	}
+
+	var ticker <-chan time.Time
+	if poll > 0 {
+		pollTicker := time.NewTicker(poll)
+		defer pollTicker.Stop()
+		ticker = pollTicker.C
+	}
+
+	w.status.WithField("commands", w.watchedCommands()).Info("Watching inputs")
+	w.start(allCommands(len(w.commands)))
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			if w.round != nil {
+				w.round.jobs.stop()
+				<-w.done
+			}
+			return nil
+		case event := <-events:
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addDirectories(notifier, []string{event.Name})
+				}
+			}
+			settle = time.After(debounce)
+		case err := <-notifyErrors:
+			config.Logger.WithError(err).Warn("File watcher error")
+		case <-ticker:
+			w.check()
+		case <-settle:
+			settle = nil
+			w.check()
+		case err := <-w.done:
+			w.finish(err)
+		}
+	}
+}
+
+func (w *watcher) check() {
+	fingerprints := w.fingerprint()
+	affected := make([]bool, len(w.commands))
+	var queue []int
+	for index := range w.commands {
+		if fingerprints[index] != w.fingerprints[index] {
+			affected[index] = true
+			queue = append(queue, index)
+		}
+	}
+	w.fingerprints = fingerprints
+	if len(queue) == 0 {
+		return
+	}
+
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, dependent := range w.dependents[curr] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	var names []string
+	for index, changed := range affected {
+		if changed {
+			names = append(names, w.commands[index].Key())
+		}
+	}
+	w.status.WithField("commands", names).Info("Inputs changed, rerunning")
+
+	if w.round == nil {
+		w.start(affected)
+		return
+	}
+
+	for position, index := range w.round.indexes {
+		if affected[index] {
+			w.round.jobs.cancel(position)
+		}
+	}
+	for index, changed := range affected {
+		w.pending[index] = w.pending[index] || changed
+	}
+}
+
+// start runs the selected commands in a new round. Unchanged parents that
+// succeeded are restored from their last result, with their recorded stdout in
+// pipeline mode, and are rerun otherwise.
+func (w *watcher) start(affected []bool) {
+	selected := slices.Clone(affected)
+	restored := make([]bool, len(w.commands))
+	var queue []int
+	for index, chosen := range affected {
+		if chosen {
+			queue = append(queue, index)
+		}
+	}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, dependency := range w.commands[curr].Dependencies {
+			if selected[dependency] {
+				continue
+			}
+			selected[dependency] = true
+			if w.reusable(dependency) {
+				restored[dependency] = true
+			} else {
+				queue = append(queue, dependency)
+			}
+		}
+	}
+
+	config := *w.config
+	config.Commands = subsetCommands(w.commands, selected, nil)
+	config.Results = nil
+	config.Only, config.Tags, config.Skip = nil, nil, nil
+	config.WithDeps, config.WithDependents = false, false
+
+	round := &watchRound{config: &config, jobs: newJobControl()}
+	var resumed *models.SavedRun
+	if anySelected(restored) {
+		resumed = &models.SavedRun{RunID: w.runID, Commands: config.Commands}
+	}
+	for index, chosen := range selected {
+		if !chosen {
+			continue
+		}
+		round.indexes = append(round.indexes, index)
+		if resumed == nil {
+			continue
+		}
+		if restored[index] {
+			resumed.Results = append(resumed.Results, w.results[index])
+		} else {
+			resumed.Results = append(resumed.Results, nil)
+		}
+	}
+	w.round = round
+
+	config.Logger.WithField("commands", len(round.indexes)).Info("Starting watch round")
+	go func() {
+		w.done <- execute(round.config, resumed, round.jobs)
+	}()
+}
+
+func (w *watcher) reusable(index int) bool {
+	result := w.results[index]
+	if result == nil || result.Status != models.RunStatusSuccess {
+		return false
+	}
+	return !w.config.UsePipeline || result.Stdout != ""
+}
+
+func (w *watcher) finish(err error) {
+	round := w.round
+	w.round = nil
+
+	var saved *models.SavedRun
+	if round.config.RecordHistory && round.config.RunID != "" {
+		saved, _ = history.NewRunStore(historyPath(round.config)).Load(round.config.RunID)
+	}
+	for position, index := range round.indexes {
+		switch {
+		case saved != nil && position < len(saved.Results) && saved.Results[position] != nil:
+			w.results[index] = saved.Results[position]
+		case position < len(round.config.Results) && round.config.Results[position].Finished():
+			w.results[index] = newSavedResult(round.config.Results[position])
+		}
+	}
+	if round.config.RunID != "" {
+		w.runID = round.config.RunID
+	}
+	w.config.Logger.WithField("run_id", round.config.RunID).Info("Finished watch round")
+
+	if err != nil {
+		w.status.WithError(err).Warn("Watch round failed, waiting for changes")
+	} else {
+		w.status.Info("Watch round done, waiting for changes")
+	}
+
+	if !anySelected(w.pending) {
+		return
+	}
+	pending := w.pending
+	w.pending = make([]bool, len(w.commands))
+	w.start(pending)
+}
+
+func (w *watcher) inputs(command *models.Command) (string, []string) {
+	vars := make(map[string]string)
+	maps.Copy(vars, w.config.Vars)
+	maps.Copy(vars, command.Vars)
+	if resolved, err := parsers.InterpolateCommand(command, vars); err == nil {
+		command = resolved
+	}
+	return commandDir(w.config, command), command.Inputs
+}
+
+func (w *watcher) fingerprint() []string {
+	fingerprints := make([]string, len(w.commands))
+	for index, command := range w.commands {
+		if len(command.Inputs) == 0 {
+			continue
+		}
+
+		dir, patterns := w.inputs(command)
+		files, err := expandGlobs(dir, patterns)
+		if err != nil {
+			w.config.Logger.WithError(err).WithField("command", command.Key()).Warn("Failed to expand inputs")
+			continue
+		}
+
+		hash := sha256.New()
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(hash, "%s %d %d\n", file, info.Size(), info.ModTime().UnixNano())
+		}
+		fingerprints[index] = hex.EncodeToString(hash.Sum(nil))
+	}
+	return fingerprints
+}
+
+func (w *watcher) watchRoots() []string {
+	var roots []string
+	for _, command := range w.commands {
+		dir, patterns := w.inputs(command)
+		for _, pattern := range patterns {
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(dir, pattern)
+			}
+			roots = append(roots, globBase(pattern))
+		}
+	}
+	return roots
+}
+
+func (w *watcher) watchedCommands() int {
+	count := 0
+	for _, command := range w.commands {
+		if len(command.Inputs) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func (w *watcher) addDirectories(notifier *fsnotify.Watcher, roots []string) error {
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root && os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !entry.IsDir() {
+				return nil
+			}
+			return notifier.Add(path)
+		})
+		if err != nil {
+			return err
+		}
+		w.config.Logger.WithFields(logrus.Fields{"root": root}).Debug("Watching directory")
+	}
+	return nil
+}
+
+func globBase(pattern string) string {
+	base := pattern
+	for strings.ContainsAny(base, "*?[") {
+		base = filepath.Dir(base)
+	}
+	for {
+		info, err := os.Stat(base)
+		if err == nil && info.IsDir() || filepath.Dir(base) == base {
+			return base
+		}
+		base = filepath.Dir(base)
+	}
+}
+
+func allCommands(count int) []bool {
+	selected := make([]bool, count)
+	for index := range selected {
+		selected[index] = true
+	}
+	return selected
+}
+
+func anySelected(selected []bool) bool {
+	for _, chosen := range selected {
+		if chosen {
+			return true
+		}
+	}
+	return false
+}
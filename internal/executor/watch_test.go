@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/history"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type roundCounter struct {
+	mu       sync.Mutex
+	started  int
+	finished int
+}
+
+func (c *roundCounter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (c *roundCounter) Fire(entry *logrus.Entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch entry.Message {
+	case "Starting watch round":
+		c.started++
+	case "Finished watch round":
+		c.finished++
+	}
+	return nil
+}
+
+func (c *roundCounter) counts() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.started, c.finished
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(schedulerDeadline)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// startWatch runs Watch in the background and returns a function that stops it
+// and fails the test unless it returns promptly.
+func startWatch(t *testing.T, config *models.Config, debounce, poll time.Duration) (*roundCounter, func()) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	counter := &roundCounter{}
+	config.Logger.AddHook(counter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, config, debounce, poll)
+	}()
+
+	return counter, func() {
+		cancel()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Watch() = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Watch did not stop after cancellation")
+		}
+	}
+}
+
+func writeInput(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchRestoresUnchangedParents(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.txt")
+	writeInput(t, input, "one")
+
+	consumer := fakeCommand("consumer", "cat", 0)
+	consumer.Inputs = []string{"*.txt"}
+	consumer.Cwd = dir
+	consumer.Outputs = []string{"stdout"}
+	config := newTestConfig(fakeCommand("producer", "echo produced"), consumer)
+	config.UsePipeline = true
+	config.RecordHistory = true
+	config.HistoryFile = filepath.Join(t.TempDir(), "history.jsonl")
+	config.Only = []string{"producer"}
+	config.WithDependents = true
+
+	counter, stop := startWatch(t, config, 0, 20*time.Millisecond)
+	waitFor(t, "the first round", func() bool { _, finished := counter.counts(); return finished == 1 })
+	writeInput(t, input, "changed")
+	waitFor(t, "the second round", func() bool { _, finished := counter.counts(); return finished == 2 })
+	stop()
+
+	runsDir := filepath.Dir(history.NewRunStore(config.HistoryFile).Dir("run"))
+	entries, err := os.ReadDir(runsDir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("got runs %v, %v; want 2", entries, err)
+	}
+	store := history.NewRunStore(config.HistoryFile)
+	var first, last *models.SavedRun
+	for _, entry := range entries {
+		run, err := store.Load(entry.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if run.ResumedFrom == "" {
+			first = run
+		} else {
+			last = run
+		}
+	}
+	if first == nil || last == nil {
+		t.Fatal("the second round did not restore anything from the first")
+	}
+	if len(last.Results) != 2 || last.Results[0] == nil || last.Results[1] == nil {
+		t.Fatalf("second round results = %v, want producer and consumer", last.Results)
+	}
+	if last.Results[0].Status != models.RunStatusSuccess || last.ResumedFrom != first.RunID {
+		t.Errorf("producer was not restored from the first round: %+v", last.Results[0])
+	}
+	if got := last.Results[1].Outputs["stdout"]; last.Results[1].Status != models.RunStatusSuccess || !strings.Contains(got, "echo produced") {
+		t.Errorf("consumer = %s with stdout %q, want the producer's output", last.Results[1].Status, got)
+	}
+}
+
+func TestWatchDebouncesChanges(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.txt")
+	writeInput(t, input, "0")
+
+	build := fakeCommand("build", "echo build")
+	build.Inputs = []string{"*.txt"}
+	build.Cwd = dir
+	config := newTestConfig(build)
+
+	counter, stop := startWatch(t, config, 200*time.Millisecond, 0)
+	defer stop()
+	waitFor(t, "the first round", func() bool { _, finished := counter.counts(); return finished == 1 })
+
+	for i := 1; i <= 5; i++ {
+		writeInput(t, input, strings.Repeat("x", i))
+		time.Sleep(20 * time.Millisecond)
+	}
+	waitFor(t, "the rerun", func() bool { _, finished := counter.counts(); return finished == 2 })
+	time.Sleep(500 * time.Millisecond)
+	if started, _ := counter.counts(); started != 2 {
+		t.Errorf("started %d rounds for one burst of changes, want 2", started)
+	}
+}
+
+func TestWatchCancelsAffectedCommands(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.txt")
+	writeInput(t, input, "one")
+
+	slow := fakeCommand("slow", "sleep 10s")
+	slow.Inputs = []string{"*.txt"}
+	slow.Cwd = dir
+	config := newTestConfig(slow)
+
+	counter, stop := startWatch(t, config, 0, 20*time.Millisecond)
+	waitFor(t, "the first round", func() bool { started, _ := counter.counts(); return started == 1 })
+
+	started := time.Now()
+	writeInput(t, input, "changed")
+	waitFor(t, "the rerun", func() bool { started, finished := counter.counts(); return finished == 1 && started == 2 })
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("cancelling the running command took %v", elapsed)
+	}
+	stop()
+}
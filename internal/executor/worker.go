@@ -1,56 +1,109 @@
 package executor
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand"
 	"os"
-	"os/exec"
-	"sync"
+	"strconv"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/unsubble/threadinator/internal/models"
 	"github.com/unsubble/threadinator/internal/parsers"
 )
 
 type Worker struct {
-	id        int
-	result    io.Reader
-	mu        sync.Mutex
-	cond      *sync.Cond
-	prev      *Worker
-	command   *models.Command
-	waitGroup *sync.WaitGroup
-	config    *models.Config
+	id      int
+	index   int
+	attempt int
+	command *models.Command
+	config  *models.Config
+	run     *runState
+	ctx     context.Context
 }
 
-func newWorker(id int, wg *sync.WaitGroup, config *models.Config) *Worker {
-	config.Logger.Infof("Creating worker with ID: %d", id)
-	w := &Worker{
-		id:        id,
-		waitGroup: wg,
-		config:    config,
+func newWorker(id int, config *models.Config, run *runState) *Worker {
+	config.Logger.WithField("thread", id).Info("Creating worker")
+	return &Worker{
+		id:     id,
+		config: config,
+		run:    run,
 	}
-	w.cond = sync.NewCond(&w.mu)
-	return w
 }
 
 func (w *Worker) perform() error {
-	defer w.waitGroup.Done()
-	err := w.executeCommand()
+	defer w.run.streams.finish(w.command, w.index)
 
-	w.mu.Lock()
-	w.cond.Broadcast()
-	w.mu.Unlock()
+	ctx, err := w.run.jobs.begin(w.index)
+	if err != nil {
+		return err
+	}
+	defer w.run.jobs.end(w.index)
+	w.ctx = ctx
+
+	if !w.run.jobs.acquire(ctx, func() { w.run.streams.starve(w.command, w.index) }) {
+		return w.interrupted(ctx)
+	}
+	defer w.run.jobs.release()
+
+	hash, err := w.checkInputs()
+	if err != nil {
+		return err
+	}
+
+	w.run.events <- commandEvent{kind: commandStarted, index: w.index}
+	if err := w.executeRepeated(); err != nil {
+		return err
+	}
+	w.storeInputs(hash)
+	return nil
+}
+
+func (w *Worker) executeRepeated() error {
+	interval := w.command.Interval
+	if interval == nil {
+		return w.executeCommand()
+	}
+
+	deadline := interval.Deadline(time.Now())
+	for w.attempt = 1; ; w.attempt++ {
+		started := time.Now()
+		err := w.executeCommand()
+		if err != nil {
+			w.logger().WithError(err).Warn("Interval run failed")
+		}
+
+		if interval.Count > 0 && w.attempt >= interval.Count {
+			return err
+		}
+
+		wait := interval.Every - time.Since(started)
+		if interval.Jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(interval.Jitter)))
+		}
+		if !deadline.IsZero() && time.Now().Add(wait).After(deadline) {
+			return err
+		}
 
-	return err
+		w.logger().WithField("wait", wait).Debug("Waiting for next interval run")
+		select {
+		case <-time.After(wait):
+		case <-w.ctx.Done():
+			return w.interrupted(w.ctx)
+		}
+	}
 }
 
 func (w *Worker) executeCommand() error {
 	w.logVerbose(fmt.Sprintf("Executing command: %s %v", w.command.Command, w.command.Args))
 
-	ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+	ctx, cancel := context.WithTimeout(w.ctx, w.config.Timeout)
 	defer cancel()
 
 	if w.command.Delay != nil {
@@ -59,39 +112,111 @@ func (w *Worker) executeCommand() error {
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, w.command.Command, w.command.Args...)
-	cmd.Env = os.Environ()
+	command, err := parsers.InterpolateCommand(w.command, w.variables())
+	if err != nil {
+		return err
+	}
 
-	if w.config.UsePipeline && w.prev != nil {
-		prevOut, err := w.getStdout()
-		if err != nil {
-			return err
-		}
-		cmd.Stdin = prevOut
+	outputFile, err := prepareOutputFile(command)
+	if err != nil {
+		return err
+	}
+	if outputFile != "" {
+		defer os.Remove(outputFile)
 	}
 
-	reader, err := cmd.StdoutPipe()
+	parents, closeParents, err := w.parentOutputs()
+	if err != nil {
+		return err
+	}
+	defer closeParents()
 
+	inputFiles, err := prepareInputFiles(command, parents)
 	if err != nil {
-		return models.NewPipeError(err)
+		return err
 	}
+	defer removeFiles(inputFiles)
 
-	if err := cmd.Start(); err != nil {
-		return models.NewCommandError(w.command.Command, err.Error())
+	executor, err := newExecutor(w, command, inputFiles)
+	if err != nil {
+		return err
 	}
 
-	buffer := make([]byte, 1024)
-	l, err := reader.Read(buffer)
+	stdin := composeStdin(command.Stdin, parents)
+	if closer, ok := stdin.(io.Closer); ok {
+		defer closer.Close()
+	}
 
-	if err != nil {
-		return models.NewBufferError(err)
+	executor.Stdin(stdin)
+	if err := executor.Start(ctx); err != nil {
+		return err
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		executor.Signal(os.Kill)
+	})
+	defer stop()
+
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		w.logStderr(executor.Stderr())
+	}()
+
+	var output io.Reader = executor.Stdout()
+	captured := &bytes.Buffer{}
+	if capturesStdout(command) {
+		output = io.TeeReader(output, captured)
+	}
+	if stream := w.run.streams[w.index]; stream != nil {
+		output = io.TeeReader(output, stream)
+		defer context.AfterFunc(ctx, stream.unblock)()
+
+		if w.run.recorder.keepsState() {
+			recorded, err := w.run.recorder.openStdout(w.index, w.attempt)
+			if err != nil {
+				w.logger().WithError(err).Warn("Failed to record output")
+			} else {
+				defer recorded.Close()
+				output = io.TeeReader(output, recorded)
+			}
+		}
+	}
+
+	if err := processCommandOutput(ctx, output, w); err != nil {
+		io.Copy(io.Discard, executor.Stdout())
+		<-stderrDone
+		executor.Wait()
+		return err
 	}
+	<-stderrDone
 
-	if w.config.UsePipeline {
-		w.result = bytes.NewBuffer(buffer[0:l])
+	if err := executor.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return w.interrupted(ctx)
+		}
+		return models.NewCommandError(w.command.Command, err.Error())
 	}
 
-	return processCommandOutput(ctx, bytes.NewBuffer(buffer[0:l]), w)
+	outputs, err := collectOutputs(command, captured.String(), outputFile)
+	if err != nil {
+		return err
+	}
+	w.config.Results[w.index].Outputs = outputs
+
+	return nil
+}
+
+func (w *Worker) variables() map[string]string {
+	vars := outputVariables(w.config.Results)
+	maps.Copy(vars, w.config.Vars)
+	maps.Copy(vars, w.command.Vars)
+
+	vars["index"] = strconv.Itoa(w.index)
+	vars["repeat"] = strconv.Itoa(w.command.Repeat)
+	vars["worker"] = strconv.Itoa(w.id)
+	vars["run_id"] = w.config.RunID
+	return vars
 }
 
 func (w *Worker) performDelay(ctx context.Context) error {
@@ -104,74 +229,84 @@ func (w *Worker) performDelay(ctx context.Context) error {
 
 	select {
 	case <-time.After(time.Duration(delay) * parsers.GetTimeUnit(timeUnit)):
-		w.config.Logger.Infof("[Thread-%d] before sleeping for %d %s", w.id, delay, timeUnit)
+		w.logger().Infof("Before sleeping for %d %s", delay, timeUnit)
 	case <-ctx.Done():
-		w.config.Logger.Errorf("Timeout exceeded for command: %s", w.command.Command)
-		return models.NewTimeoutError(w.command.Command)
+		return w.interrupted(ctx)
 	}
-	w.config.Logger.Infof("[Thread-%d] after sleeping for %d %s", w.id, delay, timeUnit)
+	w.logger().Infof("After sleeping for %d %s", delay, timeUnit)
 
 	return nil
 }
 
 func processCommandOutput(ctx context.Context, reader io.Reader, w *Worker) error {
-	buffer := make([]byte, 1024)
+	buffered := bufio.NewReader(reader)
 	for {
 		select {
 		case <-ctx.Done():
-			w.config.Logger.Errorf("Timeout exceeded for command: %s", w.command.Command)
-			return models.NewTimeoutError(w.command.Command)
+			return w.interrupted(ctx)
 		default:
-			c, err := reader.Read(buffer)
+			line, err := buffered.ReadString('\n')
+			if line != "" {
+				w.logOutput(line)
+			}
 			if err == io.EOF {
 				return nil
 			}
 			if err != nil {
-				w.config.Logger.Errorf("Error reading output: %v", err)
+				w.logger().WithError(err).Error("Error reading output")
 				return models.NewOutputReadError(err)
 			}
-			w.logOutput(string(buffer[:c]))
 		}
 	}
 }
 
-func recoverFromPanic(w *Worker, errorChan chan error) {
-	if r := recover(); r != nil {
-		errorChan <- models.NewPanicError(w.id, r)
-		w.config.Logger.Errorf("Recovered from panic in Thread-%d: %v", w.id, r)
+func (w *Worker) interrupted(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return models.NewSkipError("cancelled by a newer change")
 	}
+	w.logger().Error("Timeout exceeded")
+	return models.NewTimeoutError(w.command.Command)
 }
 
-func (w *Worker) getStdout() (io.Reader, error) {
-	w.prev.mu.Lock()
-	defer w.prev.mu.Unlock()
-
-	for w.prev.result == nil {
-		w.prev.cond.Wait()
+func recoverFromPanic(w *Worker, result *models.Result, errorChan chan error) {
+	if r := recover(); r != nil {
+		err := models.NewPanicError(w.id, r)
+		result.Finish(err)
+		errorChan <- err
+		w.logger().Errorf("Recovered from panic: %v", r)
 	}
+}
 
-	var buf bytes.Buffer
-	_, err := io.Copy(&buf, w.prev.result)
-
-	w.prev.result = bytes.NewBuffer(buf.Bytes())
-
-	if err != nil {
-		return nil, err
+func (w *Worker) logger() *logrus.Entry {
+	fields := logrus.Fields{
+		"thread":  w.id,
+		"index":   w.index,
+		"attempt": w.attempt,
 	}
-
-	return bytes.NewReader(buf.Bytes()), nil
+	if w.command != nil {
+		fields["command"] = w.command.Command
+	}
+	return w.config.Logger.WithFields(fields)
 }
 
 func (w *Worker) logVerbose(message string) {
 	if w.config.Verbose {
-		w.config.Logger.Debugf("[Thread-%d] %s", w.id, message)
+		w.logger().Debug(message)
+	}
+}
+
+func (w *Worker) logStderr(reader io.Reader) {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		w.logger().WithField("stderr", scanner.Text()).Warn("Command error output")
 	}
+	io.Copy(io.Discard, reader)
 }
 
 func (w *Worker) logOutput(output string) {
 	if !w.config.Verbose {
 		fmt.Printf("[Thread-%d] Output: %s", w.id, output)
 	} else {
-		w.config.Logger.Debugf("[Thread-%d] Output: %s", w.id, output)
+		w.logger().WithField("output", output).Debug("Command output")
 	}
 }
@@ -0,0 +1,37 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+func PrintStats(output io.Writer, stats []*JobStats) {
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "JOB\tRUNS\tP50\tP95\tFAILED\tFLAKY\tLAST")
+	for _, job := range stats {
+		last := job.Runs[len(job.Runs)-1]
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%.0f%%\t%.0f%%\t%s\n", job.Job, len(job.Runs),
+			formatDuration(job.P50), formatDuration(job.P95), job.FailureRate*100, job.Flakiness*100, last.Status)
+	}
+	writer.Flush()
+}
+
+func PrintRuns(output io.Writer, stats *JobStats) {
+	fmt.Fprintf(output, "\n%s\n", stats.Job)
+	writer := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "START\tDURATION\tSTATUS\tRUN\tERROR")
+	for _, run := range stats.Runs {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", run.Start.Format(time.DateTime),
+			formatDuration(run.Duration), run.Status, run.RunID, run.Error)
+	}
+	writer.Flush()
+}
+
+func formatDuration(duration time.Duration) string {
+	if duration == 0 {
+		return "-"
+	}
+	return duration.Round(time.Millisecond).String()
+}
@@ -0,0 +1,98 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const stateFileName = "state.json"
+
+type RunStore struct {
+	dir string
+}
+
+func NewRunStore(historyPath string) *RunStore {
+	return &RunStore{dir: filepath.Join(filepath.Dir(historyPath), "runs")}
+}
+
+func (s *RunStore) Dir(runID string) string {
+	return filepath.Join(s.dir, runID)
+}
+
+func (s *RunStore) Save(run *models.SavedRun) error {
+	return WriteRun(filepath.Join(s.Dir(run.RunID), stateFileName), run)
+}
+
+func (s *RunStore) Load(runID string) (*models.SavedRun, error) {
+	run, err := ReadRun(filepath.Join(s.Dir(runID), stateFileName))
+	var openErr *models.FileOpenError
+	if errors.As(err, &openErr) && os.IsNotExist(openErr.Cause) {
+		return nil, models.NewUnknownRunError(runID)
+	}
+	return run, err
+}
+
+func (s *RunStore) Prune(keep int) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return models.NewFileOpenError(s.dir, err)
+	}
+
+	var runs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			runs = append(runs, entry.Name())
+		}
+	}
+	if len(runs) <= keep {
+		return nil
+	}
+
+	slices.Sort(runs)
+	for _, runID := range runs[:len(runs)-keep] {
+		os.RemoveAll(s.Dir(runID))
+	}
+	return nil
+}
+
+// WriteRun keeps the file private because commands carry their resolved env.
+func WriteRun(path string, run *models.SavedRun) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return models.NewHistoryWriteError(path, err)
+	}
+
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return models.NewHistoryWriteError(path, err)
+	}
+
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, data, 0600); err != nil {
+		return models.NewHistoryWriteError(path, err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		return models.NewHistoryWriteError(path, err)
+	}
+	return nil
+}
+
+func ReadRun(path string) (*models.SavedRun, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, models.NewFileOpenError(path, err)
+	}
+
+	run := &models.SavedRun{}
+	if err := json.Unmarshal(data, run); err != nil {
+		return nil, models.NewHistoryReadError(path, 0, err)
+	}
+	return run, nil
+}
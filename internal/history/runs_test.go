@@ -0,0 +1,58 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestWriteRunUsesSnakeCaseKeys(t *testing.T) {
+	delay := 2
+	until := time.Date(2024, 5, 2, 18, 0, 0, 0, time.UTC)
+	command := &models.Command{
+		Name:         "deploy",
+		Command:      "make",
+		Args:         []string{"deploy"},
+		Times:        1,
+		Delay:        &delay,
+		Dependencies: []int{0},
+		Interval:     &models.Interval{Every: 90 * time.Second, Jitter: time.Second, UntilTime: until},
+		Host:         &models.Host{User: "deploy", Address: "web1:22"},
+		UnsetEnv:     []string{"DEBUG"},
+		Resources:    models.Resources{"cpu": 0.5},
+		Group:        &models.ConcurrencyGroup{Name: "deploys", Max: 1},
+		OutputFiles:  []string{"dist/*"},
+	}
+	run := &models.SavedRun{
+		RunID:    "20240502-091244-a1b2c3",
+		Commands: []*models.Command{{Command: "make", Args: []string{"build"}, Times: 1}, command},
+		Results:  []*models.SavedResult{{Status: models.RunStatusSuccess}, nil},
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := WriteRun(path, run); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"name": "deploy"`, `"args": [`, `"dependencies": [`, `"every": "1m30s"`, `"jitter": "1s"`, `"until_time": "2024-05-02T18:00:00Z"`, `"unset_env": [`, `"output_files": [`, `"address": "web1:22"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report is missing %s:\n%s", want, data)
+		}
+	}
+
+	read, err := ReadRun(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Commands, run.Commands) {
+		t.Fatalf("read back %+v, want %+v", read.Commands[1], command)
+	}
+}
@@ -0,0 +1,97 @@
+package history
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type JobStats struct {
+	Job         string
+	Runs        []*models.RunRecord
+	P50         time.Duration
+	P95         time.Duration
+	FailureRate float64
+	Flakiness   float64
+}
+
+func GroupByJob(records []*models.RunRecord, limit int) map[string][]*models.RunRecord {
+	jobs := make(map[string][]*models.RunRecord)
+	for _, record := range records {
+		if record.Status == models.RunStatusSkipped {
+			continue
+		}
+		jobs[record.Job] = append(jobs[record.Job], record)
+	}
+
+	for job, runs := range jobs {
+		slices.SortStableFunc(runs, func(a, b *models.RunRecord) int {
+			return a.Start.Compare(b.Start)
+		})
+		if limit > 0 && len(runs) > limit {
+			jobs[job] = runs[len(runs)-limit:]
+		}
+	}
+	return jobs
+}
+
+func Summarize(records []*models.RunRecord, limit int) []*JobStats {
+	var stats []*JobStats
+	for job, runs := range GroupByJob(records, limit) {
+		stats = append(stats, summarizeJob(job, runs))
+	}
+	slices.SortFunc(stats, func(a, b *JobStats) int {
+		return cmp.Compare(a.Job, b.Job)
+	})
+	return stats
+}
+
+func summarizeJob(job string, runs []*models.RunRecord) *JobStats {
+	stats := &JobStats{Job: job, Runs: runs}
+
+	var durations []time.Duration
+	failures, flips := 0, 0
+	for i, run := range runs {
+		if run.Status == models.RunStatusSuccess {
+			durations = append(durations, run.Duration)
+		} else {
+			failures++
+		}
+		if i > 0 && (run.Status == models.RunStatusSuccess) != (runs[i-1].Status == models.RunStatusSuccess) {
+			flips++
+		}
+	}
+
+	stats.P50 = percentile(durations, 50)
+	stats.P95 = percentile(durations, 95)
+	if len(runs) > 0 {
+		stats.FailureRate = float64(failures) / float64(len(runs))
+	}
+	if len(runs) > 1 {
+		stats.Flakiness = float64(flips) / float64(len(runs)-1)
+	}
+	return stats
+}
+
+func Estimates(records []*models.RunRecord, limit int) map[string]time.Duration {
+	estimates := make(map[string]time.Duration)
+	for _, stats := range Summarize(records, limit) {
+		if stats.P50 > 0 {
+			estimates[stats.Job] = stats.P50
+		}
+	}
+	return estimates
+}
+
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]*Store)
+)
+
+// NewStore returns the store for path. Stores are shared per file, so every
+// run and schedule in the process appends under the same lock.
+func NewStore(path string) *Store {
+	key := path
+	if abs, err := filepath.Abs(path); err == nil {
+		key = abs
+	}
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+	if store, has := stores[key]; has {
+		return store
+	}
+	store := &Store{path: path}
+	stores[key] = store
+	return store
+}
+
+func DefaultPath() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.TempDir()
+		}
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateHome, "threadinator", "history.jsonl")
+}
+
+func (s *Store) Append(records ...*models.RunRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return models.NewHistoryWriteError(s.path, err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return models.NewFileOpenError(s.path, err)
+	}
+	defer file.Close()
+
+	var lines []byte
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return models.NewHistoryWriteError(s.path, err)
+		}
+		lines = append(append(lines, data...), '\n')
+	}
+
+	if _, err := file.Write(lines); err != nil {
+		return models.NewHistoryWriteError(s.path, err)
+	}
+	return nil
+}
+
+func (s *Store) Load() ([]*models.RunRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, models.NewFileOpenError(s.path, err)
+	}
+	defer file.Close()
+
+	var records []*models.RunRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &models.RunRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, models.NewHistoryReadError(s.path, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, models.NewHistoryReadError(s.path, 0, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,42 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestStoresShareAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	if NewStore(path) != NewStore(filepath.Join(filepath.Dir(path), ".", "history.jsonl")) {
+		t.Fatal("stores for the same file are not shared")
+	}
+
+	const writers, appends = 8, 50
+	var wg sync.WaitGroup
+	for writer := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewStore(path)
+			for i := range appends {
+				record := &models.RunRecord{RunID: fmt.Sprintf("%d-%d", writer, i), Job: "job", Status: models.RunStatusSuccess}
+				if err := store.Append(record, record); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	records, err := NewStore(path).Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if len(records) != 2*writers*appends {
+		t.Fatalf("got %d records, want %d", len(records), 2*writers*appends)
+	}
+}
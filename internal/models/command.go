@@ -1,9 +1,168 @@
 package models
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	BackendLocal     = "local"
+	BackendSSH       = "ssh"
+	BackendContainer = "container"
+	BackendFake      = "fake"
+)
+
+const (
+	StdinConcat     = "concat"
+	StdinInterleave = "interleave"
+	StdinFiles      = "files"
+)
+
+const (
+	TeeBlock = "block"
+	TeeSpill = "spill"
+	TeeDrop  = "drop"
+)
+
 type Command struct {
-	Command    string
-	Args       []string
-	Times      int
-	Delay      *int
-	Dependency *int
+	Name         string            `json:"name,omitempty"`
+	Command      string            `json:"command"`
+	Args         []string          `json:"args,omitempty"`
+	Times        int               `json:"times"`
+	Repeat       int               `json:"repeat,omitempty"`
+	Delay        *int              `json:"delay,omitempty"`
+	Dependencies []int             `json:"dependencies,omitempty"`
+	Interval     *Interval         `json:"interval,omitempty"`
+	Host         *Host             `json:"host,omitempty"`
+	Vars         map[string]string `json:"vars,omitempty"`
+	Backend      string            `json:"backend,omitempty"`
+	Container    *Container        `json:"container,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	UnsetEnv     []string          `json:"unset_env,omitempty"`
+	EnvFiles     []string          `json:"env_files,omitempty"`
+	ClearEnv     bool              `json:"clear_env,omitempty"`
+	Cwd          string            `json:"cwd,omitempty"`
+	Outputs      []string          `json:"outputs,omitempty"`
+	Stdin        string            `json:"stdin,omitempty"`
+	Tee          string            `json:"tee,omitempty"`
+	Resources    Resources         `json:"resources,omitempty"`
+	Locks        []string          `json:"locks,omitempty"`
+	Group        *ConcurrencyGroup `json:"group,omitempty"`
+	Priority     int               `json:"priority,omitempty"`
+	Inputs       []string          `json:"inputs,omitempty"`
+	OutputFiles  []string          `json:"output_files,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	If           string            `json:"if,omitempty"`
+}
+
+type ConcurrencyGroup struct {
+	Name string `json:"name"`
+	Max  int    `json:"max,omitempty"`
+}
+
+type Container struct {
+	Image   string `json:"image"`
+	Runtime string `json:"runtime,omitempty"`
+	CPUs    string `json:"cpus,omitempty"`
+	Memory  string `json:"memory,omitempty"`
+}
+
+func (c *Command) Key() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return strings.Join(append([]string{c.Command}, c.Args...), " ")
+}
+
+func (c *Command) BackendName() string {
+	if c.Backend != "" {
+		return c.Backend
+	}
+	if c.Container != nil {
+		return BackendContainer
+	}
+	if c.Host != nil {
+		return BackendSSH
+	}
+	return BackendLocal
+}
+
+type Host struct {
+	User    string `json:"user"`
+	Address string `json:"address"`
+}
+
+func (h *Host) String() string {
+	return h.User + "@" + h.Address
+}
+
+type Interval struct {
+	Every      time.Duration
+	Jitter     time.Duration
+	Count      int
+	UntilAfter time.Duration
+	UntilTime  time.Time
+}
+
+// savedInterval writes the durations of an Interval as strings like "1m30s"
+// rather than nanoseconds.
+type savedInterval struct {
+	Every      string     `json:"every"`
+	Jitter     string     `json:"jitter,omitempty"`
+	Count      int        `json:"count,omitempty"`
+	UntilAfter string     `json:"until_after,omitempty"`
+	UntilTime  *time.Time `json:"until_time,omitempty"`
+}
+
+func (i Interval) MarshalJSON() ([]byte, error) {
+	saved := savedInterval{Every: i.Every.String(), Count: i.Count}
+	if i.Jitter > 0 {
+		saved.Jitter = i.Jitter.String()
+	}
+	if i.UntilAfter > 0 {
+		saved.UntilAfter = i.UntilAfter.String()
+	}
+	if !i.UntilTime.IsZero() {
+		saved.UntilTime = &i.UntilTime
+	}
+	return json.Marshal(saved)
+}
+
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var saved savedInterval
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+
+	interval := Interval{Count: saved.Count}
+	for _, field := range []struct {
+		value  string
+		target *time.Duration
+	}{
+		{saved.Every, &interval.Every},
+		{saved.Jitter, &interval.Jitter},
+		{saved.UntilAfter, &interval.UntilAfter},
+	} {
+		if field.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(field.value)
+		if err != nil {
+			return err
+		}
+		*field.target = duration
+	}
+	if saved.UntilTime != nil {
+		interval.UntilTime = *saved.UntilTime
+	}
+	*i = interval
+	return nil
+}
+
+func (i *Interval) Deadline(start time.Time) time.Time {
+	if i.UntilAfter > 0 {
+		return start.Add(i.UntilAfter)
+	}
+	return i.UntilTime
 }
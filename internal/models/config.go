@@ -7,16 +7,43 @@ import (
 )
 
 type Config struct {
-	Name        string `json:"name"`
-	ShortDesc   string `json:"short-desc"`
-	LongDesc    string `json:"long-desc"`
-	Version     string `json:"version"`
-	TimeUnit    string `json:"timeunit"`
-	TimeoutInt  int    `json:"timeout"`
-	Logger      *logrus.Logger
-	Commands    []*Command
-	ThreadCount int
-	UsePipeline bool
-	Verbose     bool
-	Timeout     time.Duration
+	Name           string            `json:"name"`
+	ShortDesc      string            `json:"short-desc"`
+	LongDesc       string            `json:"long-desc"`
+	Version        string            `json:"version"`
+	TimeUnit       string            `json:"timeunit"`
+	TimeoutInt     int               `json:"timeout"`
+	LogFormat      string            `json:"log-format"`
+	SSHIdentity    string            `json:"ssh-identity"`
+	KnownHosts     string            `json:"ssh-known-hosts"`
+	Inventory      string            `json:"inventory"`
+	Runtime        string            `json:"container-runtime"`
+	Env            map[string]string `json:"env"`
+	EnvFiles       StringList        `json:"env-file"`
+	ClearEnv       bool              `json:"clear-env"`
+	Cwd            string            `json:"cwd"`
+	Vars           map[string]string `json:"vars"`
+	TeePolicy      string            `json:"tee-policy"`
+	TeeBuffer      int               `json:"tee-buffer"`
+	Resources      Resources         `json:"resources"`
+	HistoryFile    string            `json:"history-file"`
+	RecordHistory  bool              `json:"record-history"`
+	Logger         *logrus.Logger
+	Commands       []*Command
+	Results        []*Result
+	HostGroups     []string
+	RunID          string
+	Durations      map[string]time.Duration
+	ThreadCount    int
+	UsePipeline    bool
+	Verbose        bool
+	Progress       time.Duration
+	Force          bool
+	ReportFile     string
+	Only           []string
+	Tags           []string
+	Skip           []string
+	WithDeps       bool
+	WithDependents bool
+	Timeout        time.Duration
 }
@@ -33,6 +33,20 @@ func NewTimeoutError(command string) error {
 	}
 }
 
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("Skipped: %s", e.Reason)
+}
+
+func NewSkipError(reason string) error {
+	return &SkipError{
+		Reason: reason,
+	}
+}
+
 type PipeError struct {
 	OriginalError error
 }
@@ -131,6 +145,240 @@ func NewCircularDependencyError() error {
 	return &CircularDependencyError{}
 }
 
+type EmptySelectionError struct{}
+
+func (e *EmptySelectionError) Error() string {
+	return "No commands match the selection"
+}
+
+func NewEmptySelectionError() error {
+	return &EmptySelectionError{}
+}
+
+type ExecutionError struct {
+	Failed int
+	Total  int
+}
+
+func (e *ExecutionError) Error() string {
+	return fmt.Sprintf("%d of %d commands failed", e.Failed, e.Total)
+}
+
+func NewExecutionError(failed, total int) error {
+	return &ExecutionError{
+		Failed: failed,
+		Total:  total,
+	}
+}
+
+type InterruptedError struct{}
+
+func (e *InterruptedError) Error() string {
+	return "Execution interrupted"
+}
+
+func NewInterruptedError() error {
+	return &InterruptedError{}
+}
+
+type SSHError struct {
+	Host  string
+	Cause error
+}
+
+func (e *SSHError) Error() string {
+	return fmt.Sprintf("SSH error on %s: %v", e.Host, e.Cause)
+}
+
+func NewSSHError(host string, cause error) error {
+	return &SSHError{Host: host, Cause: cause}
+}
+
+type BackendError struct {
+	Backend string
+	Message string
+}
+
+func (e *BackendError) Error() string {
+	return fmt.Sprintf("Backend '%s': %s", e.Backend, e.Message)
+}
+
+func NewBackendError(backend, message string) error {
+	return &BackendError{Backend: backend, Message: message}
+}
+
+type EnvFileError struct {
+	FilePath string
+	Line     int
+}
+
+func (e *EnvFileError) Error() string {
+	return fmt.Sprintf("Invalid entry in env file %s at line %d", e.FilePath, e.Line)
+}
+
+func NewEnvFileError(filePath string, line int) error {
+	return &EnvFileError{FilePath: filePath, Line: line}
+}
+
+type InterpolationError struct {
+	Value   string
+	Message string
+}
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("Cannot interpolate '%s': %s", e.Value, e.Message)
+}
+
+func NewInterpolationError(value, message string) error {
+	return &InterpolationError{Value: value, Message: message}
+}
+
+// Job File Errors
+type JobFileDecodeError struct {
+	FilePath string
+	Cause    error
+}
+
+func (e *JobFileDecodeError) Error() string {
+	return fmt.Sprintf("Error decoding job file %s: %v", e.FilePath, e.Cause)
+}
+
+func NewJobFileDecodeError(filePath string, cause error) error {
+	return &JobFileDecodeError{FilePath: filePath, Cause: cause}
+}
+
+type InvalidJobError struct {
+	Job     string
+	Message string
+}
+
+func (e *InvalidJobError) Error() string {
+	return fmt.Sprintf("Invalid job '%s': %s", e.Job, e.Message)
+}
+
+func NewInvalidJobError(job, message string) error {
+	return &InvalidJobError{Job: job, Message: message}
+}
+
+type UnknownJobError struct {
+	Job        string
+	Dependency string
+}
+
+func (e *UnknownJobError) Error() string {
+	return fmt.Sprintf("Job '%s' references unknown job '%s'", e.Job, e.Dependency)
+}
+
+func NewUnknownJobError(job, dependency string) error {
+	return &UnknownJobError{Job: job, Dependency: dependency}
+}
+
+type ScheduleParseError struct {
+	Expression string
+	Cause      error
+}
+
+func (e *ScheduleParseError) Error() string {
+	return fmt.Sprintf("Invalid schedule expression '%s': %v", e.Expression, e.Cause)
+}
+
+func NewScheduleParseError(expression string, cause error) error {
+	return &ScheduleParseError{Expression: expression, Cause: cause}
+}
+
+type MatrixError struct {
+	Entry   string
+	Message string
+}
+
+func (e *MatrixError) Error() string {
+	return fmt.Sprintf("Invalid matrix entry '%s': %s", e.Entry, e.Message)
+}
+
+func NewMatrixError(entry, message string) error {
+	return &MatrixError{Entry: entry, Message: message}
+}
+
+// Inventory Errors
+type InventoryDecodeError struct {
+	FilePath string
+	Cause    error
+}
+
+func (e *InventoryDecodeError) Error() string {
+	return fmt.Sprintf("Error decoding inventory file %s: %v", e.FilePath, e.Cause)
+}
+
+func NewInventoryDecodeError(filePath string, cause error) error {
+	return &InventoryDecodeError{FilePath: filePath, Cause: cause}
+}
+
+type UnknownHostGroupError struct {
+	Group string
+}
+
+func (e *UnknownHostGroupError) Error() string {
+	return fmt.Sprintf("Unknown host group '%s' in inventory", e.Group)
+}
+
+func NewUnknownHostGroupError(group string) error {
+	return &UnknownHostGroupError{Group: group}
+}
+
+// History Errors
+type HistoryWriteError struct {
+	FilePath string
+	Cause    error
+}
+
+func (e *HistoryWriteError) Error() string {
+	return fmt.Sprintf("Error writing history file %s: %v", e.FilePath, e.Cause)
+}
+
+func NewHistoryWriteError(filePath string, cause error) error {
+	return &HistoryWriteError{FilePath: filePath, Cause: cause}
+}
+
+type HistoryReadError struct {
+	FilePath string
+	Line     int
+	Cause    error
+}
+
+func (e *HistoryReadError) Error() string {
+	return fmt.Sprintf("Error reading history file %s at line %d: %v", e.FilePath, e.Line, e.Cause)
+}
+
+func NewHistoryReadError(filePath string, line int, cause error) error {
+	return &HistoryReadError{FilePath: filePath, Line: line, Cause: cause}
+}
+
+type UnknownRunError struct {
+	RunID string
+}
+
+func (e *UnknownRunError) Error() string {
+	return fmt.Sprintf("No saved state for run %s", e.RunID)
+}
+
+func NewUnknownRunError(runID string) error {
+	return &UnknownRunError{RunID: runID}
+}
+
+// Cache Errors
+type CacheError struct {
+	FilePath string
+	Cause    error
+}
+
+func (e *CacheError) Error() string {
+	return fmt.Sprintf("Error accessing cache file %s: %v", e.FilePath, e.Cause)
+}
+
+func NewCacheError(filePath string, cause error) error {
+	return &CacheError{FilePath: filePath, Cause: cause}
+}
+
 // Config Errors
 type ConfigParseError struct {
 	Cause error
@@ -193,6 +441,35 @@ func NewConfigChangeError(cause error) error {
 	return &ConfigChangeError{Cause: cause}
 }
 
+// Condition Errors
+type ConditionError struct {
+	Expression string
+	Message    string
+}
+
+func (e *ConditionError) Error() string {
+	return fmt.Sprintf("Invalid condition %q: %s", e.Expression, e.Message)
+}
+
+func NewConditionError(expression, message string) error {
+	return &ConditionError{Expression: expression, Message: message}
+}
+
+// Option Errors
+type OptionError struct {
+	Key     string
+	Value   string
+	Message string
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("Invalid option %s=%s: %s", e.Key, e.Value, e.Message)
+}
+
+func NewOptionError(key, value, message string) error {
+	return &OptionError{Key: key, Value: value, Message: message}
+}
+
 // Log Level Errors
 type LogLevelError struct {
 	LogLevel string
@@ -218,3 +495,15 @@ func (e *UnsupportedLogLevelError) Error() string {
 func NewUnsupportedLogLevelError(logLevel string) error {
 	return &UnsupportedLogLevelError{LogLevel: logLevel}
 }
+
+type LogFormatError struct {
+	LogFormat string
+}
+
+func (e *LogFormatError) Error() string {
+	return fmt.Sprintf("Unsupported log format: %s", e.LogFormat)
+}
+
+func NewLogFormatError(logFormat string) error {
+	return &LogFormatError{LogFormat: logFormat}
+}
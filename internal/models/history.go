@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	RunStatusSuccess = "success"
+	RunStatusFailed  = "failed"
+	RunStatusSkipped = "skipped"
+	RunStatusTimeout = "timeout"
+)
+
+type RunRecord struct {
+	RunID    string        `json:"run_id,omitempty"`
+	Job      string        `json:"job"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"`
+	Status   string        `json:"status"`
+	Error    string        `json:"error,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+type Inventory struct {
+	Groups map[string]*HostGroup        `json:"groups"`
+	Hosts  map[string]map[string]string `json:"hosts"`
+}
+
+type HostGroup struct {
+	Hosts []string          `json:"hosts"`
+	Vars  map[string]string `json:"vars"`
+}
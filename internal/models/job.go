@@ -0,0 +1,115 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type JobFile struct {
+	Vars   map[string]string `json:"vars"`
+	Jobs   []*Job            `json:"jobs"`
+	Groups []*JobGroup       `json:"groups"`
+}
+
+type Job struct {
+	Name         string             `json:"name"`
+	Command      string             `json:"command"`
+	DependsOn    StringList         `json:"depends-on"`
+	Delay        *int               `json:"delay"`
+	Times        int                `json:"times"`
+	Every        string             `json:"every"`
+	Jitter       string             `json:"jitter"`
+	Until        string             `json:"until"`
+	Count        int                `json:"count"`
+	Host         string             `json:"host"`
+	Backend      string             `json:"backend"`
+	Image        string             `json:"image"`
+	Runtime      string             `json:"runtime"`
+	CPUs         string             `json:"cpus"`
+	Memory       string             `json:"memory"`
+	Env          map[string]*string `json:"env"`
+	EnvFiles     StringList         `json:"env-file"`
+	ClearEnv     bool               `json:"clear-env"`
+	Cwd          string             `json:"cwd"`
+	Matrix       *Matrix            `json:"matrix"`
+	Outputs      StringList         `json:"outputs"`
+	Stdin        string             `json:"stdin"`
+	Tee          string             `json:"tee"`
+	Resources    Resources          `json:"resources"`
+	Lock         StringList         `json:"lock"`
+	Group        string             `json:"group"`
+	Priority     int                `json:"priority"`
+	Inputs       StringList         `json:"inputs"`
+	OutputFiles  StringList         `json:"output-files"`
+	Tags         StringList         `json:"tags"`
+	If           string             `json:"if"`
+	Schedule     string             `json:"schedule"`
+	AllowOverlap bool               `json:"allow-overlap"`
+}
+
+type JobGroup struct {
+	Name         string   `json:"name"`
+	Jobs         []string `json:"jobs"`
+	Schedule     string   `json:"schedule"`
+	AllowOverlap bool     `json:"allow-overlap"`
+}
+
+type Matrix struct {
+	Axes    map[string][]string
+	Include []map[string]string
+	Exclude []map[string]string
+}
+
+func (m *Matrix) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	raw := make(map[string]any)
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	m.Axes = make(map[string][]string)
+	for key, value := range raw {
+		entries, ok := value.([]any)
+		if !ok {
+			return NewMatrixError(key, "expected a list")
+		}
+
+		switch key {
+		case "include", "exclude":
+			combinations, err := matrixCombinations(key, entries)
+			if err != nil {
+				return err
+			}
+			if key == "include" {
+				m.Include = combinations
+			} else {
+				m.Exclude = combinations
+			}
+		default:
+			for _, entry := range entries {
+				m.Axes[key] = append(m.Axes[key], fmt.Sprint(entry))
+			}
+		}
+	}
+
+	return nil
+}
+
+func matrixCombinations(key string, entries []any) ([]map[string]string, error) {
+	var combinations []map[string]string
+	for _, entry := range entries {
+		object, ok := entry.(map[string]any)
+		if !ok {
+			return nil, NewMatrixError(key, "expected a list of objects")
+		}
+		combination := make(map[string]string)
+		for name, value := range object {
+			combination[name] = fmt.Sprint(value)
+		}
+		combinations = append(combinations, combination)
+	}
+	return combinations, nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type Result struct {
+	Index    int
+	Command  *Command
+	Status   string
+	Err      error
+	Start    time.Time
+	End      time.Time
+	Outputs  map[string]string
+	Waited   time.Duration
+	Hash     string
+	Cached   bool
+	Restored bool
+	Done     chan struct{}
+}
+
+func NewResult(index int, command *Command) *Result {
+	return &Result{
+		Index:   index,
+		Command: command,
+		Done:    make(chan struct{}),
+	}
+}
+
+func (r *Result) Finished() bool {
+	select {
+	case <-r.Done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Result) Restore(saved *SavedResult) {
+	r.Status = saved.Status
+	r.Start = saved.Start
+	r.End = saved.End
+	r.Outputs = saved.Outputs
+	r.Restored = true
+	close(r.Done)
+}
+
+func (r *Result) Finish(err error) {
+	r.End = time.Now()
+	r.Err = err
+
+	var timeoutErr *TimeoutError
+	var skipErr *SkipError
+	switch {
+	case err == nil:
+		r.Status = RunStatusSuccess
+	case errors.As(err, &skipErr):
+		r.Status = RunStatusSkipped
+	case errors.As(err, &timeoutErr):
+		r.Status = RunStatusTimeout
+	default:
+		r.Status = RunStatusFailed
+	}
+	close(r.Done)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type SavedRun struct {
+	RunID       string            `json:"run_id"`
+	ResumedFrom string            `json:"resumed_from,omitempty"`
+	Created     time.Time         `json:"created"`
+	Updated     time.Time         `json:"updated"`
+	Pipeline    bool              `json:"pipeline"`
+	ThreadCount int               `json:"thread_count"`
+	Vars        map[string]string `json:"vars,omitempty"`
+	Commands    []*Command        `json:"commands"`
+	Results     []*SavedResult    `json:"results"`
+}
+
+type SavedResult struct {
+	Status  string            `json:"status,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Start   time.Time         `json:"start"`
+	End     time.Time         `json:"end"`
+	Outputs map[string]string `json:"outputs,omitempty"`
+	Stdout  string            `json:"stdout,omitempty"`
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type StringList []string
+
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single == "" {
+			*l = nil
+		} else {
+			*l = StringList{single}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+type Quantity float64
+
+type Resources map[string]Quantity
+
+const CPUResource = "cpu"
+
+var quantitySuffixes = map[byte]float64{
+	'k': 1 << 10,
+	'm': 1 << 20,
+	'g': 1 << 30,
+	't': 1 << 40,
+}
+
+func ParseQuantity(value string) (Quantity, error) {
+	number := strings.TrimSpace(value)
+	multiplier := 1.0
+	if number != "" {
+		if suffix, has := quantitySuffixes[strings.ToLower(number)[len(number)-1]]; has {
+			number = number[:len(number)-1]
+			multiplier = suffix
+		}
+	}
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || parsed < 0 {
+		return 0, NewOptionError("resources", value, "expected a positive number with an optional K, M, G or T suffix")
+	}
+	return Quantity(parsed * multiplier), nil
+}
+
+// ParseResourceQuantity parses the amount of a named resource. cpu is counted
+// in cores and, as in Kubernetes, accepts an m suffix for millicores.
+func ParseResourceQuantity(name, value string) (Quantity, error) {
+	if name != CPUResource {
+		return ParseQuantity(value)
+	}
+
+	number := strings.TrimSpace(value)
+	multiplier := 1.0
+	if strings.HasSuffix(number, "m") {
+		number = strings.TrimSuffix(number, "m")
+		multiplier = 0.001
+	}
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || parsed < 0 {
+		return 0, NewOptionError("resources", value, "expected a number of cores or millicores (e.g. 2 or 500m)")
+	}
+	return Quantity(parsed * multiplier), nil
+}
+
+func (r *Resources) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	resources := make(Resources, len(raw))
+	for name, value := range raw {
+		var amount string
+		if err := json.Unmarshal(value, &amount); err == nil {
+			quantity, err := ParseResourceQuantity(name, amount)
+			if err != nil {
+				return err
+			}
+			resources[name] = quantity
+			continue
+		}
+
+		var quantity Quantity
+		if err := json.Unmarshal(value, &quantity); err != nil {
+			return err
+		}
+		resources[name] = quantity
+	}
+	*r = resources
+	return nil
+}
+
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		if number < 0 {
+			return NewOptionError("resources", string(data), "expected a positive number")
+		}
+		*q = Quantity(number)
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseQuantity(value)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
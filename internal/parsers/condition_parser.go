@@ -0,0 +1,262 @@
+package parsers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type ConditionContext struct {
+	Parents []string
+	Vars    map[string]string
+	Env     map[string]string
+	Dir     string
+}
+
+type conditionParser struct {
+	expression string
+	tokens     []string
+	position   int
+	context    *ConditionContext
+}
+
+func ValidateCondition(expression string) error {
+	_, err := EvaluateCondition(expression, nil)
+	return err
+}
+
+func EvaluateCondition(expression string, context *ConditionContext) (bool, error) {
+	tokens, err := tokenizeCondition(expression)
+	if err != nil {
+		return false, err
+	}
+	if len(tokens) == 0 {
+		return false, models.NewConditionError(expression, "empty condition")
+	}
+
+	p := &conditionParser{expression: expression, tokens: tokens, context: context}
+	value, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.position < len(p.tokens) {
+		return false, p.error("unexpected " + p.tokens[p.position])
+	}
+	return truthy(value), nil
+}
+
+func tokenizeCondition(expression string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expression); {
+		char := expression[i]
+		switch {
+		case char == ' ' || char == '\t':
+			i++
+		case strings.HasPrefix(expression[i:], "&&"), strings.HasPrefix(expression[i:], "||"),
+			strings.HasPrefix(expression[i:], "=="), strings.HasPrefix(expression[i:], "!="):
+			tokens = append(tokens, expression[i:i+2])
+			i += 2
+		case strings.ContainsRune("!(),", rune(char)):
+			tokens = append(tokens, string(char))
+			i++
+		case char == '\'' || char == '"':
+			end := strings.IndexByte(expression[i+1:], char)
+			if end < 0 {
+				return nil, models.NewConditionError(expression, "unterminated string")
+			}
+			tokens = append(tokens, expression[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(expression[i:], "${"):
+			end := strings.IndexByte(expression[i:], '}')
+			if end < 0 {
+				return nil, models.NewConditionError(expression, "unterminated variable reference")
+			}
+			tokens = append(tokens, expression[i:i+end+1])
+			i += end + 1
+		default:
+			start := i
+			for i < len(expression) && !strings.ContainsRune(" \t!(),&|=\"'", rune(expression[i])) {
+				i++
+			}
+			if i == start {
+				return nil, models.NewConditionError(expression, "unexpected "+string(char))
+			}
+			tokens = append(tokens, expression[start:i])
+		}
+	}
+	return tokens, nil
+}
+
+func (p *conditionParser) parseOr() (string, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("||") {
+		var right string
+		right, err = p.parseAnd()
+		left = boolValue(truthy(left) || truthy(right))
+	}
+	return left, err
+}
+
+func (p *conditionParser) parseAnd() (string, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.accept("&&") {
+		var right string
+		right, err = p.parseUnary()
+		left = boolValue(truthy(left) && truthy(right))
+	}
+	return left, err
+}
+
+func (p *conditionParser) parseUnary() (string, error) {
+	if p.accept("!") {
+		value, err := p.parseUnary()
+		return boolValue(!truthy(value)), err
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return "", err
+	}
+	for _, operator := range []string{"==", "!="} {
+		if p.accept(operator) {
+			right, err := p.parseOperand()
+			return boolValue((left == right) == (operator == "==")), err
+		}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseOperand() (string, error) {
+	if p.position >= len(p.tokens) {
+		return "", p.error("unexpected end of condition")
+	}
+	token := p.tokens[p.position]
+	p.position++
+
+	switch {
+	case token == "(":
+		value, err := p.parseOr()
+		if err == nil && !p.accept(")") {
+			err = p.error("missing )")
+		}
+		return value, err
+	case token[0] == '\'' || token[0] == '"':
+		return token[1 : len(token)-1], nil
+	case strings.HasPrefix(token, "${"):
+		return p.variable(strings.TrimSpace(token[2 : len(token)-1])), nil
+	case strings.HasPrefix(token, "env."):
+		return p.env(token[len("env."):]), nil
+	case strings.ContainsRune("!),&|=", rune(token[0])):
+		return "", p.error("unexpected " + token)
+	case p.accept("("):
+		return p.call(token)
+	}
+	return token, nil
+}
+
+func (p *conditionParser) call(name string) (string, error) {
+	var args []string
+	for !p.accept(")") {
+		if len(args) > 0 && !p.accept(",") {
+			return "", p.error("expected , or ) after argument")
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		args = append(args, arg)
+	}
+
+	switch name {
+	case "success", "failure", "skipped", "always":
+		if len(args) != 0 {
+			return "", p.error(name + "() takes no arguments")
+		}
+		return boolValue(p.status(name)), nil
+	case "exists":
+		if len(args) != 1 {
+			return "", p.error("exists() takes one path")
+		}
+		return boolValue(p.exists(args[0])), nil
+	}
+	return "", p.error("unknown function " + name + "()")
+}
+
+// status reports on the parents: success() needs every parent to have run and
+// succeeded, so a skipped parent makes it false without being a failure().
+func (p *conditionParser) status(name string) bool {
+	if name == "always" || p.context == nil {
+		return true
+	}
+
+	failed, skipped := false, false
+	for _, status := range p.context.Parents {
+		switch status {
+		case models.RunStatusFailed, models.RunStatusTimeout:
+			failed = true
+		case models.RunStatusSkipped:
+			skipped = true
+		}
+	}
+
+	switch name {
+	case "failure":
+		return failed
+	case "skipped":
+		return skipped
+	}
+	return !failed && !skipped
+}
+
+func (p *conditionParser) variable(name string) string {
+	if p.context == nil {
+		return ""
+	}
+	return p.context.Vars[name]
+}
+
+func (p *conditionParser) env(name string) string {
+	if p.context == nil {
+		return ""
+	}
+	if value, has := p.context.Env[name]; has {
+		return value
+	}
+	return os.Getenv(name)
+}
+
+func (p *conditionParser) exists(path string) bool {
+	if p.context == nil || path == "" {
+		return false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.context.Dir, path)
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (p *conditionParser) accept(token string) bool {
+	if p.position < len(p.tokens) && p.tokens[p.position] == token {
+		p.position++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) error(message string) error {
+	return models.NewConditionError(p.expression, message)
+}
+
+func truthy(value string) bool {
+	return value != "" && value != "false" && value != "0"
+}
+
+func boolValue(value bool) string {
+	if value {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,152 @@
+package parsers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestTokenizeCondition(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       []string
+		fails      bool
+	}{
+		{expression: "success() && !failure()", want: []string{"success", "(", ")", "&&", "!", "failure", "(", ")"}},
+		{expression: "${ env }=='prod'||env.CI!=\"\"", want: []string{"${ env }", "==", "'prod'", "||", "env.CI", "!=", `""`}},
+		{expression: "exists('a, (b)')", want: []string{"exists", "(", "'a, (b)'", ")"}},
+		{expression: `"it's" == 'say "hi"'`, want: []string{`"it's"`, "==", `'say "hi"'`}},
+		{expression: "\t", want: nil},
+		{expression: "'open", fails: true},
+		{expression: "${name", fails: true},
+		{expression: "a & b", fails: true},
+		{expression: "a = b", fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			got, err := tokenizeCondition(test.expression)
+			if test.fails {
+				if err == nil {
+					t.Fatalf("tokenizeCondition(%q) = %q, want an error", test.expression, got)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, test.want) {
+				t.Fatalf("tokenizeCondition(%q) = %q, %v; want %q", test.expression, got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "present"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	context := &ConditionContext{
+		Vars: map[string]string{"env": "prod", "empty": "", "zero": "0"},
+		Env:  map[string]string{"CI": "true"},
+		Dir:  dir,
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		// && binds tighter than ||, ! tighter than both, and comparisons tighter than !.
+		{expression: "'x' || '' && ''", want: true},
+		{expression: "('x' || '') && ''", want: false},
+		{expression: "!'' && 'x'", want: true},
+		{expression: "!('x' && '')", want: true},
+		{expression: "!'a' == 'a'", want: false},
+		{expression: "!!'x'", want: true},
+
+		{expression: "${env} == 'prod'", want: true},
+		{expression: "${ env } != \"prod\"", want: false},
+		{expression: "${missing} == ''", want: true},
+		{expression: "${empty}", want: false},
+		{expression: "${zero}", want: false},
+		{expression: "false", want: false},
+		{expression: "env.CI == 'true'", want: true},
+
+		{expression: "'a && b' == \"a && b\"", want: true},
+		{expression: "'(x)' == '(x)'", want: true},
+		{expression: "\"it's\" != 'it'", want: true},
+
+		{expression: "exists('present')", want: true},
+		{expression: "exists(\"" + filepath.Join(dir, "present") + "\")", want: true},
+		{expression: "exists('missing')", want: false},
+		{expression: "exists('')", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			got, err := EvaluateCondition(test.expression, context)
+			if err != nil || got != test.want {
+				t.Fatalf("EvaluateCondition(%q) = %v, %v; want %v", test.expression, got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestConditionStatusFunctions(t *testing.T) {
+	success, failed, timeout, skipped := models.RunStatusSuccess, models.RunStatusFailed, models.RunStatusTimeout, models.RunStatusSkipped
+	tests := []struct {
+		name    string
+		parents []string
+		success bool
+		failure bool
+		skipped bool
+	}{
+		{name: "no parents", success: true},
+		{name: "all succeeded", parents: []string{success, success}, success: true},
+		{name: "one failed", parents: []string{success, failed}, failure: true},
+		{name: "one timed out", parents: []string{timeout}, failure: true},
+		{name: "one skipped", parents: []string{success, skipped}, skipped: true},
+		{name: "failed and skipped", parents: []string{failed, skipped}, failure: true, skipped: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			context := &ConditionContext{Parents: test.parents}
+			for expression, want := range map[string]bool{
+				"success()": test.success,
+				"failure()": test.failure,
+				"skipped()": test.skipped,
+				"always()":  true,
+			} {
+				if got, err := EvaluateCondition(expression, context); err != nil || got != want {
+					t.Errorf("%s = %v, %v; want %v", expression, got, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"success(",
+		"(success()",
+		"success() &&",
+		"success(1)",
+		"exists()",
+		"exists('a', 'b')",
+		"deploy()",
+		"'a' 'b'",
+		"== 'a'",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			err := ValidateCondition(expression)
+			var conditionErr *models.ConditionError
+			if !errors.As(err, &conditionErr) {
+				t.Fatalf("ValidateCondition(%q) = %v, want a ConditionError", expression, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package parsers
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func ParseEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, models.NewFileOpenError(path, err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		name, value, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, models.NewEnvFileError(path, lineNumber)
+		}
+
+		values[name] = unquote(strings.TrimSpace(value))
+	}
+
+	return values, scanner.Err()
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
@@ -0,0 +1,44 @@
+package parsers
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+const defaultSSHPort = "22"
+
+func ParseHost(spec string) (*models.Host, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.New("empty host")
+	}
+
+	username, address, found := strings.Cut(spec, "@")
+	if !found {
+		username, address = currentUser(), spec
+	}
+	if username == "" || address == "" {
+		return nil, errors.New("expected [user@]host[:port]")
+	}
+
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(strings.Trim(address, "[]"), defaultSSHPort)
+	}
+
+	return &models.Host{User: username, Address: address}, nil
+}
+
+func currentUser() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return ""
+}
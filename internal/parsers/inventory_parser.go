@@ -0,0 +1,110 @@
+package parsers
+
+import (
+	"encoding/json"
+	"maps"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func ParseInventory(path string) (*models.Inventory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, models.NewFileOpenError(path, err)
+	}
+	defer file.Close()
+
+	inventory := &models.Inventory{}
+	if err := json.NewDecoder(file).Decode(inventory); err != nil {
+		return nil, models.NewInventoryDecodeError(path, err)
+	}
+
+	return inventory, nil
+}
+
+type inventoryHost struct {
+	host *models.Host
+	vars map[string]string
+}
+
+func resolveHosts(inventory *models.Inventory, groups []string) ([]*inventoryHost, error) {
+	var hosts []*inventoryHost
+	seen := make(map[string]bool)
+
+	for _, name := range groups {
+		group, has := inventory.Groups[name]
+		if !has {
+			return nil, models.NewUnknownHostGroupError(name)
+		}
+
+		for _, spec := range group.Hosts {
+			vars := make(map[string]string)
+			maps.Copy(vars, group.Vars)
+			maps.Copy(vars, inventory.Hosts[spec])
+
+			host, err := ParseHost(hostSpec(spec, vars))
+			if err != nil {
+				return nil, models.NewOptionError("host", spec, err.Error())
+			}
+			if seen[host.String()] {
+				continue
+			}
+			seen[host.String()] = true
+
+			delete(vars, "user")
+			delete(vars, "port")
+			hosts = append(hosts, &inventoryHost{host: host, vars: vars})
+		}
+	}
+
+	return hosts, nil
+}
+
+func hostSpec(spec string, vars map[string]string) string {
+	if user := vars["user"]; user != "" && !strings.Contains(spec, "@") {
+		spec = user + "@" + spec
+	}
+	if port := vars["port"]; port != "" {
+		_, address, _ := strings.Cut(spec, "@")
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			spec = spec + ":" + port
+		}
+	}
+	return spec
+}
+
+func ExpandHosts(commands []*models.Command, inventory *models.Inventory, groups []string) ([]*models.Command, error) {
+	hosts, err := resolveHosts(inventory, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, command := range commands {
+		if command.Host != nil {
+			return nil, models.NewOptionError("host", command.Host.String(), "cannot be combined with --hosts")
+		}
+	}
+
+	var expanded []*models.Command
+	for hostIdx, host := range hosts {
+		offset := hostIdx * len(commands)
+		for _, command := range commands {
+			clone := *command
+			clone.Host = host.host
+			clone.Vars = host.vars
+			clone.Dependencies = nil
+			for _, dependency := range command.Dependencies {
+				if dependency >= 0 && dependency < len(commands) {
+					dependency += offset
+				}
+				clone.Dependencies = append(clone.Dependencies, dependency)
+			}
+			expanded = append(expanded, &clone)
+		}
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,203 @@
+package parsers
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func ParseJobFile(path string) (*models.JobFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, models.NewFileOpenError(path, err)
+	}
+	defer file.Close()
+
+	jobFile := &models.JobFile{}
+	if err := json.NewDecoder(file).Decode(jobFile); err != nil {
+		return nil, models.NewJobFileDecodeError(path, err)
+	}
+
+	if err := validateJobFile(jobFile); err != nil {
+		return nil, err
+	}
+
+	return jobFile, nil
+}
+
+func validateJobFile(jobFile *models.JobFile) error {
+	names := make(map[string]bool)
+	for _, job := range jobFile.Jobs {
+		if strings.TrimSpace(job.Name) == "" {
+			return models.NewInvalidJobError(job.Command, "missing name")
+		}
+		if names[job.Name] {
+			return models.NewInvalidJobError(job.Name, "duplicate name")
+		}
+		if strings.TrimSpace(job.Command) == "" {
+			return models.NewInvalidJobError(job.Name, "missing command")
+		}
+		if job.Schedule != "" {
+			if _, err := ParseSchedule(job.Schedule); err != nil {
+				return err
+			}
+		}
+		names[job.Name] = true
+	}
+
+	for _, job := range jobFile.Jobs {
+		if job.Matrix != nil && len(matrixCombinations(job.Matrix)) == 0 {
+			return models.NewInvalidJobError(job.Name, "matrix has no combinations")
+		}
+		for _, dependency := range job.DependsOn {
+			template, _, _ := strings.Cut(dependency, "[")
+			if !names[template] {
+				return models.NewUnknownJobError(job.Name, dependency)
+			}
+		}
+	}
+
+	for _, group := range jobFile.Groups {
+		if strings.TrimSpace(group.Name) == "" {
+			return models.NewInvalidJobError(group.Schedule, "missing group name")
+		}
+		for _, name := range group.Jobs {
+			if !names[name] {
+				return models.NewUnknownJobError(group.Name, name)
+			}
+		}
+		if _, err := ParseSchedule(group.Schedule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func ParseSchedule(expression string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(strings.TrimSpace(expression))
+	if err != nil {
+		return nil, models.NewScheduleParseError(expression, err)
+	}
+	return schedule, nil
+}
+
+func BuildCommands(jobs []*models.Job, offset int) ([]*models.Command, error) {
+	var expanded []*models.Job
+	members := make(map[string][]string)
+	for _, job := range jobs {
+		for _, matrixJob := range expandMatrix(job) {
+			members[job.Name] = append(members[job.Name], matrixJob.Name)
+			expanded = append(expanded, matrixJob)
+		}
+	}
+
+	positions := make(map[string][]int)
+	position := offset
+	for _, job := range expanded {
+		for range max(job.Times, 1) {
+			positions[job.Name] = append(positions[job.Name], position)
+			position++
+		}
+	}
+
+	var commands []*models.Command
+	for _, job := range expanded {
+		name, args := splitFields(job.Command)
+		command := &models.Command{
+			Name:    job.Name,
+			Command: name,
+			Args:    args,
+			Times:   max(job.Times, 1),
+			Delay:   job.Delay,
+		}
+
+		if err := applyJobOptions(command, job); err != nil {
+			return nil, models.NewInvalidJobError(job.Name, err.Error())
+		}
+
+		for _, dependency := range job.DependsOn {
+			targets, has := members[dependency]
+			if !has {
+				targets = []string{dependency}
+			}
+
+			found := false
+			for _, target := range targets {
+				if target == job.Name {
+					continue
+				}
+				command.Dependencies = append(command.Dependencies, positions[target]...)
+				found = found || len(positions[target]) > 0
+			}
+			if !found {
+				return nil, models.NewUnknownJobError(job.Name, dependency)
+			}
+		}
+
+		for repeat := range command.Times {
+			clone := *command
+			clone.Repeat = repeat
+			commands = append(commands, &clone)
+		}
+	}
+
+	return commands, nil
+}
+
+func applyJobOptions(command *models.Command, job *models.Job) error {
+	options := []option{
+		{"every", job.Every},
+		{"jitter", job.Jitter},
+		{"until", job.Until},
+		{"host", job.Host},
+		{"backend", job.Backend},
+		{"image", job.Image},
+		{"runtime", job.Runtime},
+		{"cpus", job.CPUs},
+		{"memory", job.Memory},
+		{"cwd", job.Cwd},
+		{"stdin", job.Stdin},
+		{"tee", job.Tee},
+		{"group", job.Group},
+		{"if", job.If},
+	}
+	if job.Count > 0 {
+		options = append(options, option{"count", strconv.Itoa(job.Count)})
+	}
+
+	for _, option := range options {
+		if option.value == "" {
+			continue
+		}
+		if err := applyOption(command, option.key, option.value); err != nil {
+			return err
+		}
+	}
+
+	for name, value := range job.Env {
+		if value == nil {
+			command.UnsetEnv = append(command.UnsetEnv, name)
+			continue
+		}
+		if command.Env == nil {
+			command.Env = make(map[string]string)
+		}
+		command.Env[name] = *value
+	}
+	command.EnvFiles = append(command.EnvFiles, job.EnvFiles...)
+	command.Outputs = append(command.Outputs, job.Outputs...)
+	command.ClearEnv = job.ClearEnv
+	command.Resources = job.Resources
+	command.Locks = append(command.Locks, job.Lock...)
+	command.Priority = job.Priority
+	command.Inputs = append(command.Inputs, job.Inputs...)
+	command.OutputFiles = append(command.OutputFiles, job.OutputFiles...)
+	command.Tags = append(command.Tags, job.Tags...)
+
+	return validateOptions(command)
+}
@@ -0,0 +1,147 @@
+package parsers
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func expandMatrix(job *models.Job) []*models.Job {
+	if job.Matrix == nil {
+		return []*models.Job{job}
+	}
+
+	var jobs []*models.Job
+	for _, combination := range matrixCombinations(job.Matrix) {
+		vars := make(map[string]string)
+		for name, value := range combination {
+			vars["matrix."+name] = value
+		}
+		jobs = append(jobs, substituteJob(job, matrixJobName(job.Name, combination), vars))
+	}
+	return jobs
+}
+
+func matrixCombinations(matrix *models.Matrix) []map[string]string {
+	var combinations []map[string]string
+	axes := slices.Sorted(maps.Keys(matrix.Axes))
+	if len(axes) > 0 {
+		combinations = []map[string]string{{}}
+	}
+
+	for _, axis := range axes {
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range matrix.Axes[axis] {
+				extended := maps.Clone(combination)
+				extended[axis] = value
+				next = append(next, extended)
+			}
+		}
+		combinations = next
+	}
+
+	combinations = slices.DeleteFunc(combinations, func(combination map[string]string) bool {
+		return slices.ContainsFunc(matrix.Exclude, func(exclude map[string]string) bool {
+			return matchesCombination(combination, exclude)
+		})
+	})
+
+	for _, include := range matrix.Include {
+		combinations = includeCombination(combinations, include, matrix.Axes)
+	}
+
+	return combinations
+}
+
+// includeCombination adds the include's extra keys to every combination whose
+// axis values it matches, and appends it as a new combination when none does.
+// Axis values are never overwritten, keys added by an earlier include can be.
+func includeCombination(combinations []map[string]string, include map[string]string, axes map[string][]string) []map[string]string {
+	axisValues := make(map[string]string)
+	for name, value := range include {
+		if _, isAxis := axes[name]; isAxis {
+			axisValues[name] = value
+		}
+	}
+
+	extended := false
+	for _, combination := range combinations {
+		if !matchesCombination(combination, axisValues) {
+			continue
+		}
+		maps.Copy(combination, include)
+		extended = true
+	}
+	if !extended {
+		combinations = append(combinations, maps.Clone(include))
+	}
+	return combinations
+}
+
+func matchesCombination(combination, pattern map[string]string) bool {
+	for name, value := range pattern {
+		if combination[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func matrixJobName(name string, combination map[string]string) string {
+	var parts []string
+	for _, axis := range slices.Sorted(maps.Keys(combination)) {
+		parts = append(parts, axis+"="+combination[axis])
+	}
+	return name + "[" + strings.Join(parts, ",") + "]"
+}
+
+func substituteJob(job *models.Job, name string, vars map[string]string) *models.Job {
+	substitute := func(value string) string {
+		result, _ := expandVariables(value, func(name string) (string, bool) {
+			value, has := vars[name]
+			return value, has
+		}, false)
+		return result
+	}
+
+	clone := *job
+	clone.Name = name
+	clone.Matrix = nil
+	clone.Command = substitute(job.Command)
+	clone.Host = substitute(job.Host)
+	clone.Image = substitute(job.Image)
+	clone.Runtime = substitute(job.Runtime)
+	clone.CPUs = substitute(job.CPUs)
+	clone.Memory = substitute(job.Memory)
+	clone.Cwd = substitute(job.Cwd)
+	clone.If = substitute(job.If)
+
+	if job.Env != nil {
+		clone.Env = make(map[string]*string, len(job.Env))
+		for envName, value := range job.Env {
+			if value != nil {
+				substituted := substitute(*value)
+				value = &substituted
+			}
+			clone.Env[envName] = value
+		}
+	}
+
+	clone.EnvFiles = nil
+	for _, path := range job.EnvFiles {
+		clone.EnvFiles = append(clone.EnvFiles, substitute(path))
+	}
+	clone.Inputs = nil
+	for _, pattern := range job.Inputs {
+		clone.Inputs = append(clone.Inputs, substitute(pattern))
+	}
+	clone.OutputFiles = nil
+	for _, pattern := range job.OutputFiles {
+		clone.OutputFiles = append(clone.OutputFiles, substitute(pattern))
+	}
+
+	return &clone
+}
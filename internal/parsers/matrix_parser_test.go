@@ -0,0 +1,92 @@
+package parsers
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestExpandMatrixInclude(t *testing.T) {
+	axes := map[string][]string{"os": {"linux", "darwin"}, "go": {"1.22", "1.23"}}
+	tests := []struct {
+		name    string
+		include []map[string]string
+		exclude []map[string]string
+		want    []string
+	}{
+		{
+			name: "cartesian product",
+			want: []string{"test[go=1.22,os=darwin]", "test[go=1.22,os=linux]", "test[go=1.23,os=darwin]", "test[go=1.23,os=linux]"},
+		},
+		{
+			name:    "exclude",
+			exclude: []map[string]string{{"os": "darwin"}},
+			want:    []string{"test[go=1.22,os=linux]", "test[go=1.23,os=linux]"},
+		},
+		{
+			name:    "include extends matching combinations",
+			include: []map[string]string{{"os": "linux", "cgo": "1"}},
+			want:    []string{"test[cgo=1,go=1.22,os=linux]", "test[cgo=1,go=1.23,os=linux]", "test[go=1.22,os=darwin]", "test[go=1.23,os=darwin]"},
+		},
+		{
+			name:    "include without axes extends every combination",
+			include: []map[string]string{{"race": "true"}},
+			want:    []string{"test[go=1.22,os=darwin,race=true]", "test[go=1.22,os=linux,race=true]", "test[go=1.23,os=darwin,race=true]", "test[go=1.23,os=linux,race=true]"},
+		},
+		{
+			name:    "include of an existing combination",
+			include: []map[string]string{{"os": "linux", "go": "1.23"}},
+			want:    []string{"test[go=1.22,os=darwin]", "test[go=1.22,os=linux]", "test[go=1.23,os=darwin]", "test[go=1.23,os=linux]"},
+		},
+		{
+			name:    "include of a new axis value",
+			include: []map[string]string{{"os": "windows", "go": "1.23"}},
+			want:    []string{"test[go=1.22,os=darwin]", "test[go=1.22,os=linux]", "test[go=1.23,os=darwin]", "test[go=1.23,os=linux]", "test[go=1.23,os=windows]"},
+		},
+		{
+			name:    "include of an excluded combination adds it back",
+			exclude: []map[string]string{{"os": "darwin", "go": "1.22"}},
+			include: []map[string]string{{"os": "darwin", "go": "1.22", "cgo": "0"}},
+			want:    []string{"test[cgo=0,go=1.22,os=darwin]", "test[go=1.22,os=linux]", "test[go=1.23,os=darwin]", "test[go=1.23,os=linux]"},
+		},
+		{
+			name:    "later includes overwrite added keys",
+			include: []map[string]string{{"cgo": "0"}, {"os": "linux", "cgo": "1"}},
+			want:    []string{"test[cgo=0,go=1.22,os=darwin]", "test[cgo=0,go=1.23,os=darwin]", "test[cgo=1,go=1.22,os=linux]", "test[cgo=1,go=1.23,os=linux]"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			job := &models.Job{Name: "test", Command: "make test", Matrix: &models.Matrix{Axes: axes, Include: test.include, Exclude: test.exclude}}
+			var got []string
+			for _, expanded := range expandMatrix(job) {
+				got = append(got, expanded.Name)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, test.want) {
+				t.Fatalf("expanded %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatrixRejectsMalformedEntries(t *testing.T) {
+	for _, matrix := range []string{
+		`{"os": "linux"}`,
+		`{"os": ["linux"], "include": {"os": "windows"}}`,
+		`{"os": ["linux"], "exclude": ["linux"]}`,
+	} {
+		t.Run(matrix, func(t *testing.T) {
+			var parsed models.Matrix
+			err := json.Unmarshal([]byte(matrix), &parsed)
+			var matrixErr *models.MatrixError
+			if !errors.As(err, &matrixErr) {
+				t.Fatalf("Unmarshal(%s) = %v, want a MatrixError", matrix, err)
+			}
+		})
+	}
+}
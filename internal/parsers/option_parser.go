@@ -0,0 +1,315 @@
+package parsers
+
+import (
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+type option struct {
+	key   string
+	value string
+}
+
+func extractOptions(commandStr string) (string, []string) {
+	trimmed := strings.TrimSpace(commandStr)
+	if !strings.HasSuffix(trimmed, "]") {
+		return commandStr, nil
+	}
+
+	start := strings.LastIndex(trimmed, "[")
+	if start < 0 {
+		return commandStr, nil
+	}
+
+	tokens := tokenizeOptions(trimmed[start+1 : len(trimmed)-1])
+	for _, token := range tokens {
+		if !strings.Contains(token, "=") {
+			return commandStr, nil
+		}
+	}
+
+	return strings.TrimSpace(trimmed[:start]), tokens
+}
+
+func tokenizeOptions(options string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		quote   byte
+	)
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(options); i++ {
+		char := options[i]
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			} else {
+				current.WriteByte(char)
+			}
+		case char == '\'' || char == '"':
+			quote = char
+		case char == ' ' || char == '\t':
+			flush()
+		default:
+			current.WriteByte(char)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+func applyOptions(command *models.Command, tokens []string) error {
+	for _, token := range tokens {
+		key, value, _ := strings.Cut(token, "=")
+		if err := applyOption(command, strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)); err != nil {
+			return err
+		}
+	}
+	return validateOptions(command)
+}
+
+func applyOption(command *models.Command, key, value string) error {
+	switch key {
+	case "every", "jitter", "until", "count":
+		return applyIntervalOption(command, key, value)
+	case "host":
+		host, err := ParseHost(value)
+		if err != nil {
+			return models.NewOptionError(key, value, err.Error())
+		}
+		command.Host = host
+		return nil
+	case "image", "runtime", "cpus", "memory":
+		return applyContainerOption(command, key, value)
+	case "env", "unset-env", "env-file", "clear-env", "cwd":
+		return applyEnvironmentOption(command, key, value)
+	case "name":
+		command.Name = value
+		return nil
+	case "outputs":
+		command.Outputs = append(command.Outputs, strings.Split(value, ",")...)
+		return nil
+	case "depends-on":
+		for _, item := range splitList(value) {
+			dependency, err := strconv.Atoi(item)
+			if err != nil {
+				return models.NewOptionError(key, value, "expected a comma separated list of command indexes")
+			}
+			command.Dependencies = append(command.Dependencies, dependency)
+		}
+		return nil
+	case "stdin":
+		switch value {
+		case models.StdinConcat, models.StdinInterleave, models.StdinFiles:
+			command.Stdin = value
+			return nil
+		}
+		return models.NewOptionError(key, value, "expected concat, interleave or files")
+	case "tee":
+		if !validTeePolicy(value) {
+			return models.NewOptionError(key, value, "expected block, spill or drop")
+		}
+		command.Tee = value
+		return nil
+	case "resources":
+		resources, err := ParseResources(value)
+		if err != nil {
+			return err
+		}
+		if command.Resources == nil {
+			command.Resources = make(models.Resources)
+		}
+		maps.Copy(command.Resources, resources)
+		return nil
+	case "inputs":
+		command.Inputs = append(command.Inputs, splitList(value)...)
+		return nil
+	case "output-files":
+		command.OutputFiles = append(command.OutputFiles, splitList(value)...)
+		return nil
+	case "if":
+		if err := ValidateCondition(value); err != nil {
+			return err
+		}
+		command.If = value
+		return nil
+	case "tags":
+		command.Tags = append(command.Tags, splitList(value)...)
+		return nil
+	case "priority":
+		priority, err := strconv.Atoi(value)
+		if err != nil {
+			return models.NewOptionError(key, value, "expected an integer")
+		}
+		command.Priority = priority
+		return nil
+	case "lock":
+		locks := splitList(value)
+		if len(locks) == 0 {
+			return models.NewOptionError(key, value, "expected a lock name")
+		}
+		command.Locks = append(command.Locks, locks...)
+		return nil
+	case "group":
+		group, err := parseConcurrencyGroup(value)
+		if err != nil {
+			return err
+		}
+		command.Group = group
+		return nil
+	case "backend":
+		switch value {
+		case models.BackendLocal, models.BackendSSH, models.BackendContainer, models.BackendFake:
+			command.Backend = value
+			return nil
+		}
+		return models.NewOptionError(key, value, "unknown backend")
+	}
+	return models.NewOptionError(key, value, "unknown option")
+}
+
+func applyIntervalOption(command *models.Command, key, value string) error {
+	if command.Interval == nil {
+		command.Interval = &models.Interval{}
+	}
+	interval := command.Interval
+
+	switch key {
+	case "every", "jitter":
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return models.NewOptionError(key, value, "expected a positive duration")
+		}
+		if key == "every" {
+			interval.Every = duration
+		} else {
+			interval.Jitter = duration
+		}
+	case "until":
+		after, deadline, err := ParseDeadline(value)
+		if err != nil {
+			return models.NewOptionError(key, value, "expected a duration, a clock time or an RFC 3339 timestamp")
+		}
+		interval.UntilAfter = after
+		interval.UntilTime = deadline
+	case "count":
+		count, err := strconv.Atoi(value)
+		if err != nil || count <= 0 {
+			return models.NewOptionError(key, value, "expected a positive integer")
+		}
+		interval.Count = count
+	}
+	return nil
+}
+
+func applyContainerOption(command *models.Command, key, value string) error {
+	if value == "" {
+		return models.NewOptionError(key, value, "expected a value")
+	}
+	if command.Container == nil {
+		command.Container = &models.Container{}
+	}
+
+	switch key {
+	case "image":
+		command.Container.Image = value
+	case "runtime":
+		command.Container.Runtime = value
+	case "cpus":
+		command.Container.CPUs = value
+	case "memory":
+		command.Container.Memory = value
+	}
+	return nil
+}
+
+func applyEnvironmentOption(command *models.Command, key, value string) error {
+	switch key {
+	case "env":
+		name, envValue, found := strings.Cut(value, "=")
+		if !found || name == "" {
+			return models.NewOptionError(key, value, "expected NAME=VALUE")
+		}
+		if command.Env == nil {
+			command.Env = make(map[string]string)
+		}
+		command.Env[name] = envValue
+	case "unset-env":
+		if value == "" {
+			return models.NewOptionError(key, value, "expected a variable name")
+		}
+		command.UnsetEnv = append(command.UnsetEnv, value)
+	case "env-file":
+		if value == "" {
+			return models.NewOptionError(key, value, "expected a file path")
+		}
+		command.EnvFiles = append(command.EnvFiles, value)
+	case "clear-env":
+		clear, err := strconv.ParseBool(value)
+		if err != nil {
+			return models.NewOptionError(key, value, "expected true or false")
+		}
+		command.ClearEnv = clear
+	case "cwd":
+		command.Cwd = value
+	}
+	return nil
+}
+
+func parseConcurrencyGroup(value string) (*models.ConcurrencyGroup, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, models.NewOptionError("group", value, "expected NAME [max=N]")
+	}
+
+	group := &models.ConcurrencyGroup{Name: fields[0]}
+	if len(fields) == 2 {
+		limit, found := strings.CutPrefix(fields[1], "max=")
+		max, err := strconv.Atoi(limit)
+		if !found || err != nil || max <= 0 {
+			return nil, models.NewOptionError("group", value, "expected NAME [max=N] with a positive N")
+		}
+		group.Max = max
+	}
+	return group, nil
+}
+
+func validTeePolicy(policy string) bool {
+	switch policy {
+	case models.TeeBlock, models.TeeSpill, models.TeeDrop:
+		return true
+	}
+	return false
+}
+
+func validateOptions(command *models.Command) error {
+	if command.Backend == models.BackendSSH && command.Host == nil {
+		return models.NewOptionError("backend", command.Backend, "requires a host")
+	}
+	if command.Container != nil && command.Container.Image == "" {
+		return models.NewOptionError("image", "", "required when runtime, cpus or memory is set")
+	}
+	if command.Backend == models.BackendContainer && command.Container == nil {
+		return models.NewOptionError("backend", command.Backend, "requires an image")
+	}
+	if command.Interval != nil && command.Interval.Every <= 0 {
+		return models.NewOptionError("every", "", "required when jitter, until or count is set")
+	}
+	if command.Interval != nil && command.Times > 1 {
+		return models.NewOptionError("every", command.Interval.Every.String(), "cannot be combined with times; use count instead")
+	}
+	return nil
+}
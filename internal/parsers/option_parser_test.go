@@ -0,0 +1,59 @@
+package parsers
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestIntervalRejectsRepeatCount(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name  string
+		parse func() error
+		valid bool
+	}{
+		{
+			name: "command line",
+			parse: func() error {
+				_, err := splitCommand("echo hi:0|0|3 [every=1s]", logger)
+				return err
+			},
+		},
+		{
+			name: "command line with count",
+			parse: func() error {
+				_, err := splitCommand("echo hi [every=1s count=3]", logger)
+				return err
+			},
+			valid: true,
+		},
+		{
+			name: "job file",
+			parse: func() error {
+				_, err := BuildCommands([]*models.Job{{Name: "poll", Command: "echo hi", Times: 2, Every: "1s"}}, 0)
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.parse()
+			if test.valid {
+				if err != nil {
+					t.Fatalf("got %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "cannot be combined with times") {
+				t.Fatalf("got %v, want every rejected with times", err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package parsers
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"math/rand"
 	"os"
 	"strconv"
@@ -59,6 +60,109 @@ func ParseArgs(config *models.Config, cmd *cobra.Command) error {
 		fmt.Printf("%s version %s\n", config.Name, config.Version)
 	}
 
+	if err := ParseCommonArgs(config, cmd); err != nil {
+		return err
+	}
+
+	configSettings, _ := flags.GetString("cfg")
+	configSettingsStr := strings.TrimSpace(configSettings)
+	if configSettingsStr != "" {
+		if err := changeConfigSettings(configSettingsStr); err != nil {
+			return models.NewConfigChangeError(err)
+		}
+		config.Logger.Info("Config successfully changed.")
+		return nil
+	}
+
+	commandsStr, _ := flags.GetString("execute")
+	commandsStr = strings.TrimSpace(commandsStr)
+	commands, err := parseCommands(commandsStr, config.Logger)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range commands {
+		var dependencies []int
+		for _, dependency := range cmd.Dependencies {
+			if dependency < 0 {
+				dependency += len(config.Commands)
+			}
+			dependencies = append(dependencies, dependency)
+		}
+		for repeat := range cmd.Times {
+			clone := *cmd
+			clone.Repeat = repeat
+			clone.Dependencies = dependencies
+			config.Commands = append(config.Commands, &clone)
+		}
+	}
+
+	var fileVars map[string]string
+	jobFilePath, _ := flags.GetString("file")
+	if jobFilePath != "" {
+		jobFile, err := ParseJobFile(jobFilePath)
+		if err != nil {
+			return err
+		}
+		jobCommands, err := BuildCommands(jobFile.Jobs, len(config.Commands))
+		if err != nil {
+			return err
+		}
+		config.Commands = append(config.Commands, jobCommands...)
+		fileVars = jobFile.Vars
+	}
+
+	if err := ApplyVars(config, cmd, fileVars); err != nil {
+		return err
+	}
+
+	hostGroups, _ := flags.GetString("hosts")
+	if hostGroups = strings.TrimSpace(hostGroups); hostGroups != "" {
+		inventoryPath, _ := flags.GetString("inventory")
+		inventory, err := ParseInventory(inventoryPath)
+		if err != nil {
+			return err
+		}
+		config.HostGroups = splitList(hostGroups)
+		config.Commands, err = ExpandHosts(config.Commands, inventory, config.HostGroups)
+		if err != nil {
+			return err
+		}
+	}
+
+	only, _ := flags.GetString("only")
+	tags, _ := flags.GetString("tags")
+	skip, _ := flags.GetString("skip")
+	config.Only = splitList(only)
+	config.Tags = splitList(tags)
+	config.Skip = splitList(skip)
+	config.WithDeps, _ = flags.GetBool("with-deps")
+	config.WithDependents, _ = flags.GetBool("with-dependents")
+
+	if config.TeePolicy != "" && !validTeePolicy(config.TeePolicy) {
+		return models.NewOptionError("tee-policy", config.TeePolicy, "expected block, spill or drop")
+	}
+
+	if config.ThreadCount <= 0 {
+		config.ThreadCount = len(config.Commands)
+	}
+
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func ParseCommonArgs(config *models.Config, cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
 	logLevel, _ := flags.GetString("log-level")
 	level, err := logrus.ParseLevel(logLevel)
 
@@ -78,48 +182,62 @@ func ParseArgs(config *models.Config, cmd *cobra.Command) error {
 		config.Logger.SetLevel(level)
 	}
 
-	config.Logger.SetFormatter(&logrus.TextFormatter{
-		ForceColors:               true,
-		EnvironmentOverrideColors: true,
-		DisableTimestamp:          true,
-		PadLevelText:              true,
-	})
-
-	configSettings, _ := flags.GetString("cfg")
-	configSettingsStr := strings.TrimSpace(configSettings)
-	if configSettingsStr != "" {
-		if err := changeConfigSettings(configSettingsStr); err != nil {
-			return models.NewConfigChangeError(err)
+	logFormat, _ := flags.GetString("log-format")
+	formatter, err := parseLogFormat(logFormat)
+	if err != nil {
+		return err
+	}
+	config.LogFormat = logFormat
+	config.Logger.SetFormatter(formatter)
+
+	resources, _ := flags.GetString("resources")
+	if resources = strings.TrimSpace(resources); resources != "" {
+		capacity, err := ParseResources(resources)
+		if err != nil {
+			return err
 		}
-		config.Logger.Info("Config successfully changed.")
-		return nil
+		if config.Resources == nil {
+			config.Resources = make(models.Resources)
+		}
+		maps.Copy(config.Resources, capacity)
 	}
 
-	commandsStr, _ := flags.GetString("execute")
-	commandsStr = strings.TrimSpace(commandsStr)
-	commands := parseCommands(commandsStr, config.Logger)
+	config.Progress, _ = flags.GetDuration("progress")
+	config.Force, _ = flags.GetBool("force")
+	config.ReportFile, _ = flags.GetString("report")
+
+	if noHistory, _ := flags.GetBool("no-history"); noHistory {
+		config.RecordHistory = false
+	}
 
 	timeoutFlag, _ := flags.GetInt("timeout")
 	config.TimeoutInt = timeoutFlag
 	config.Timeout = time.Duration(timeoutFlag) * GetTimeUnit(config.TimeUnit)
 
-	for _, cmd := range commands {
-		for range cmd.Times {
-			if cmd.Dependency != nil && *cmd.Dependency < 0 {
-				*cmd.Dependency = len(config.Commands) + *cmd.Dependency
-			}
-			config.Commands = append(config.Commands, cmd)
-		}
-	}
+	return nil
+}
 
-	if config.ThreadCount <= 0 {
-		config.ThreadCount = len(config.Commands)
+func parseLogFormat(format string) (logrus.Formatter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		return &logrus.TextFormatter{
+			ForceColors:               true,
+			EnvironmentOverrideColors: true,
+			DisableTimestamp:          true,
+			PadLevelText:              true,
+		}, nil
+	case "logfmt":
+		return &logrus.TextFormatter{
+			DisableColors: true,
+			FullTimestamp: true,
+		}, nil
+	case "json":
+		return &logrus.JSONFormatter{}, nil
 	}
-
-	return nil
+	return nil, models.NewLogFormatError(format)
 }
 
-func parseCommands(commands string, logger *logrus.Logger) []*models.Command {
+func parseCommands(commands string, logger *logrus.Logger) ([]*models.Command, error) {
 	logger.Infof("Parsing commands: %s", commands)
 	var (
 		commandSlice []*models.Command
@@ -139,7 +257,10 @@ func parseCommands(commands string, logger *logrus.Logger) []*models.Command {
 			}
 		case ';':
 			if !isQuoted && (i == 0 || commands[i-1] != '\\') {
-				cmd := splitCommand(strings.TrimSpace(commands[start:i]), logger)
+				cmd, err := splitCommand(strings.TrimSpace(commands[start:i]), logger)
+				if err != nil {
+					return nil, err
+				}
 				commandSlice = append(commandSlice, cmd)
 				start = i + 1
 			}
@@ -147,24 +268,27 @@ func parseCommands(commands string, logger *logrus.Logger) []*models.Command {
 	}
 
 	if start < len(commands) {
-		cmd := splitCommand(strings.TrimSpace(commands[start:]), logger)
+		cmd, err := splitCommand(strings.TrimSpace(commands[start:]), logger)
+		if err != nil {
+			return nil, err
+		}
 		commandSlice = append(commandSlice, cmd)
 	}
 
 	logger.Infof("Parsed commands: %+v", commandSlice)
-	return commandSlice
+	return commandSlice, nil
 }
 
 func sanitizeCommand(command string) string {
 	return strings.Trim(command, "\" '")
 }
 
-func splitCommand(commandStr string, logger *logrus.Logger) *models.Command {
+func splitCommand(commandStr string, logger *logrus.Logger) (*models.Command, error) {
 	logger.Infof("Splitting command: %s", commandStr)
-	commandStr = sanitizeCommand(commandStr)
+	commandStr, options := extractOptions(sanitizeCommand(commandStr))
 	extrasIndex := strings.LastIndex(commandStr, ":")
 
-	var dependency *int
+	var dependencies []int
 	var delay *int
 	times := 1
 
@@ -174,28 +298,45 @@ func splitCommand(commandStr string, logger *logrus.Logger) *models.Command {
 		if t != nil && *t > 0 {
 			times = *t
 		}
-		dependency = dep
+		if dep != nil {
+			dependencies = []int{*dep}
+		}
 		delay = del
 		commandStr = commandStr[:extrasIndex]
 	}
 
+	name, args := splitFields(commandStr)
+	if name == "" {
+		logger.Warn("Empty command detected")
+		return &models.Command{}, nil
+	}
+
+	command := &models.Command{
+		Command:      name,
+		Args:         args,
+		Times:        times,
+		Delay:        delay,
+		Dependencies: dependencies,
+	}
+
+	if err := applyOptions(command, options); err != nil {
+		return nil, err
+	}
+
+	return command, nil
+}
+
+func splitFields(commandStr string) (string, []string) {
 	parts := strings.Fields(commandStr)
 	if len(parts) == 0 {
-		logger.Warn("Empty command detected")
-		return &models.Command{}
+		return "", nil
 	}
 
 	for index, arg := range parts[1:] {
 		parts[index+1] = sanitizeCommand(arg)
 	}
 
-	return &models.Command{
-		Command:    parts[0],
-		Args:       parts[1:],
-		Times:      times,
-		Delay:      delay,
-		Dependency: dependency,
-	}
+	return parts[0], parts[1:]
 }
 
 func parseExtras(extras string) (*int, *int, *int) {
@@ -0,0 +1,25 @@
+package parsers
+
+import (
+	"strings"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func ParseResources(value string) (models.Resources, error) {
+	resources := make(models.Resources)
+	for _, item := range splitList(value) {
+		name, amount, found := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, models.NewOptionError("resources", value, "expected NAME=AMOUNT pairs")
+		}
+
+		quantity, err := models.ParseResourceQuantity(name, amount)
+		if err != nil {
+			return nil, err
+		}
+		resources[name] = quantity
+	}
+	return resources, nil
+}
@@ -0,0 +1,58 @@
+package parsers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestParseResources(t *testing.T) {
+	tests := []struct {
+		value string
+		want  models.Resources
+		fails bool
+	}{
+		{value: "cpu=2,db=1", want: models.Resources{"cpu": 2, "db": 1}},
+		{value: "cpu=500m", want: models.Resources{"cpu": 0.5}},
+		{value: "mem=500m", want: models.Resources{"mem": 500 << 20}},
+		{value: "mem=2G, gpu=1", want: models.Resources{"mem": 2 << 30, "gpu": 1}},
+		{value: "cpu=2G", fails: true},
+		{value: "cpu=-1", fails: true},
+		{value: "mem", fails: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			got, err := ParseResources(test.value)
+			if test.fails {
+				if err == nil {
+					t.Fatalf("ParseResources(%q) = %v, want an error", test.value, got)
+				}
+				return
+			}
+			if err != nil || len(got) != len(test.want) {
+				t.Fatalf("ParseResources(%q) = %v, %v; want %v", test.value, got, err, test.want)
+			}
+			for name, amount := range test.want {
+				if got[name] != amount {
+					t.Errorf("%s = %v, want %v", name, got[name], amount)
+				}
+			}
+		})
+	}
+}
+
+func TestResourcesUnmarshalJSON(t *testing.T) {
+	var resources models.Resources
+	if err := json.Unmarshal([]byte(`{"cpu": "250m", "mem": "1G", "db": 1}`), &resources); err != nil {
+		t.Fatal(err)
+	}
+	if resources["cpu"] != 0.25 || resources["mem"] != 1<<30 || resources["db"] != 1 {
+		t.Fatalf("resources = %v", resources)
+	}
+
+	if err := json.Unmarshal([]byte(`{"cpu": "1k"}`), &resources); err == nil {
+		t.Fatal("cpu with a size suffix was accepted")
+	}
+}
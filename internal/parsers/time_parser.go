@@ -23,3 +23,25 @@ func GetTimeUnit(unit string) time.Duration {
 	logrus.Fatal("Unknown time unit format")
 	return 0
 }
+
+func ParseDeadline(value string) (time.Duration, time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration, time.Time{}, nil
+	}
+
+	if deadline, err := time.Parse(time.RFC3339, value); err == nil {
+		return 0, deadline, nil
+	}
+
+	clock, err := time.ParseInLocation("15:04", value, time.Local)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	now := time.Now()
+	deadline := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	if deadline.Before(now) {
+		deadline = deadline.AddDate(0, 0, 1)
+	}
+	return 0, deadline, nil
+}
@@ -0,0 +1,141 @@
+package parsers
+
+import (
+	"maps"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func ApplyVars(config *models.Config, cmd *cobra.Command, fileVars map[string]string) error {
+	vars := make(map[string]string)
+	maps.Copy(vars, config.Vars)
+	maps.Copy(vars, fileVars)
+
+	cliVars, _ := cmd.Flags().GetStringArray("var")
+	for _, entry := range cliVars {
+		name, value, found := strings.Cut(entry, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return models.NewOptionError("var", entry, "expected NAME=VALUE")
+		}
+		vars[strings.TrimSpace(name)] = value
+	}
+
+	config.Vars = vars
+	return nil
+}
+
+func Interpolate(value string, vars map[string]string) (string, error) {
+	return expandVariables(value, func(name string) (string, bool) {
+		if resolved, has := vars[name]; has {
+			return resolved, true
+		}
+		return os.LookupEnv(name)
+	}, true)
+}
+
+func expandVariables(value string, lookup func(string) (string, bool), strict bool) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		if value[i+1] == '$' && strings.HasPrefix(value[i+2:], "{") {
+			if !strict {
+				builder.WriteByte('$')
+			}
+			builder.WriteByte('$')
+			i++
+			continue
+		}
+
+		if value[i+1] != '{' {
+			builder.WriteByte(value[i])
+			continue
+		}
+
+		end := strings.IndexByte(value[i+2:], '}')
+		if end < 0 {
+			if !strict {
+				builder.WriteString(value[i:])
+				break
+			}
+			return "", models.NewInterpolationError(value, "unterminated variable reference")
+		}
+
+		reference := value[i : i+3+end]
+		name := strings.TrimSpace(value[i+2 : i+2+end])
+		resolved, has := lookup(name)
+		switch {
+		case has:
+			builder.WriteString(resolved)
+		case strict:
+			return "", models.NewInterpolationError(value, "undefined variable "+name)
+		default:
+			builder.WriteString(reference)
+		}
+		i += end + 2
+	}
+
+	return builder.String(), nil
+}
+
+func InterpolateCommand(command *models.Command, vars map[string]string) (*models.Command, error) {
+	resolved := *command
+	var err error
+
+	interpolate := func(value string) string {
+		if err != nil {
+			return value
+		}
+		var result string
+		result, err = Interpolate(value, vars)
+		return result
+	}
+
+	resolved.Command = interpolate(command.Command)
+	resolved.Cwd = interpolate(command.Cwd)
+
+	resolved.Args = make([]string, len(command.Args))
+	for i, arg := range command.Args {
+		resolved.Args[i] = interpolate(arg)
+	}
+
+	if command.Env != nil {
+		resolved.Env = make(map[string]string, len(command.Env))
+		for name, value := range command.Env {
+			resolved.Env[name] = interpolate(value)
+		}
+	}
+
+	resolved.EnvFiles = make([]string, len(command.EnvFiles))
+	for i, path := range command.EnvFiles {
+		resolved.EnvFiles[i] = interpolate(path)
+	}
+
+	resolved.Inputs = make([]string, len(command.Inputs))
+	for i, pattern := range command.Inputs {
+		resolved.Inputs[i] = interpolate(pattern)
+	}
+
+	resolved.OutputFiles = make([]string, len(command.OutputFiles))
+	for i, pattern := range command.OutputFiles {
+		resolved.OutputFiles[i] = interpolate(pattern)
+	}
+
+	if command.Container != nil {
+		container := *command.Container
+		container.Image = interpolate(container.Image)
+		resolved.Container = &container
+	}
+
+	return &resolved, err
+}
@@ -0,0 +1,76 @@
+package parsers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/unsubble/threadinator/internal/models"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{"name": "world", "empty": "", "matrix.os": "linux"}
+	lookup := func(name string) (string, bool) {
+		value, has := vars[name]
+		return value, has
+	}
+
+	tests := []struct {
+		value  string
+		strict bool
+		want   string
+		fails  bool
+	}{
+		{value: "no references", strict: true, want: "no references"},
+		{value: "hello ${name}", strict: true, want: "hello world"},
+		{value: "${ name }-${matrix.os}", strict: true, want: "world-linux"},
+		{value: "[${empty}]", strict: true, want: "[]"},
+		{value: "$name and $ alone", strict: true, want: "$name and $ alone"},
+		{value: "cost $", strict: true, want: "cost $"},
+
+		{value: "${missing}", strict: true, fails: true},
+		{value: "${missing}", want: "${missing}"},
+		{value: "${name} ${missing}", want: "world ${missing}"},
+
+		{value: "$${name}", strict: true, want: "${name}"},
+		{value: "$${name}", want: "$${name}"},
+		{value: "$$HOME", strict: true, want: "$$HOME"},
+
+		{value: "${name", strict: true, fails: true},
+		{value: "${name} ${rest", want: "world ${rest"},
+	}
+
+	for _, test := range tests {
+		name := test.value
+		if test.strict {
+			name += " strict"
+		}
+		t.Run(name, func(t *testing.T) {
+			got, err := expandVariables(test.value, lookup, test.strict)
+			if test.fails {
+				var interpolationErr *models.InterpolationError
+				if !errors.As(err, &interpolationErr) {
+					t.Fatalf("expandVariables(%q) = %q, %v; want an InterpolationError", test.value, got, err)
+				}
+				return
+			}
+			if err != nil || got != test.want {
+				t.Fatalf("expandVariables(%q) = %q, %v; want %q", test.value, got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestInterpolateEscapesAfterSubstitution(t *testing.T) {
+	// Job files substitute matrix values first without strict checking, and
+	// the escape must survive until the command is interpolated.
+	substituted, err := expandVariables("echo $${HOME} ${matrix.os} ${later}", func(name string) (string, bool) {
+		return "linux", name == "matrix.os"
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Interpolate(substituted, map[string]string{"later": "now"})
+	if want := "echo ${HOME} linux now"; err != nil || got != want {
+		t.Fatalf("Interpolate(%q) = %q, %v; want %q", substituted, got, err, want)
+	}
+}